@@ -73,3 +73,39 @@ func (c *QuizClient) GetInstance(ctx context.Context, req *pb.GetInstanceRequest
 func (c *QuizClient) GetHostingInstances(ctx context.Context, req *pb.GetHostingInstancesRequest) (*pb.GetHostingInstancesResponse, error) {
 	return c.client.GetHostingInstances(ctx, req)
 }
+
+func (c *QuizClient) GetParticipatingInstances(ctx context.Context, req *pb.GetParticipatingInstancesRequest) (*pb.GetParticipatingInstancesResponse, error) {
+	return c.client.GetParticipatingInstances(ctx, req)
+}
+
+func (c *QuizClient) GetParticipantResult(ctx context.Context, req *pb.GetParticipantResultRequest) (*pb.GetParticipantResultResponse, error) {
+	return c.client.GetParticipantResult(ctx, req)
+}
+
+func (c *QuizClient) GetAvailableInstances(ctx context.Context, req *pb.GetAvailableInstancesRequest) (*pb.GetAvailableInstancesResponse, error) {
+	return c.client.GetAvailableInstances(ctx, req)
+}
+
+func (c *QuizClient) GetReview(ctx context.Context, req *pb.GetReviewRequest) (*pb.GetReviewResponse, error) {
+	return c.client.GetReview(ctx, req)
+}
+
+func (c *QuizClient) GetUngradedAnswers(ctx context.Context, req *pb.GetUngradedAnswersRequest) (*pb.GetUngradedAnswersResponse, error) {
+	return c.client.GetUngradedAnswers(ctx, req)
+}
+
+func (c *QuizClient) GradeAnswer(ctx context.Context, req *pb.GradeAnswerRequest) (*pb.GradeAnswerResponse, error) {
+	return c.client.GradeAnswer(ctx, req)
+}
+
+func (c *QuizClient) PublishResults(ctx context.Context, req *pb.PublishResultsRequest) (*pb.PublishResultsResponse, error) {
+	return c.client.PublishResults(ctx, req)
+}
+
+func (c *QuizClient) GetInstanceAnalytics(ctx context.Context, req *pb.GetInstanceAnalyticsRequest) (*pb.GetInstanceAnalyticsResponse, error) {
+	return c.client.GetInstanceAnalytics(ctx, req)
+}
+
+func (c *QuizClient) GetInstanceResults(ctx context.Context, req *pb.GetInstanceResultsRequest) (*pb.GetInstanceResultsResponse, error) {
+	return c.client.GetInstanceResults(ctx, req)
+}
@@ -1,18 +1,25 @@
 package dto
 
 type QuizSettings struct {
-	RandomOrder        bool  `json:"random_order"`
-	TimeLimitTotal     int32 `json:"time_limit_total"`
-	ShowCorrectAnswers bool  `json:"show_correct_answers"`
-	AllowReview        bool  `json:"allow_review"`
+	RandomOrder           bool   `json:"random_order"`
+	TimeLimitTotal        int32  `json:"time_limit_total"`
+	ShowCorrectAnswers    bool   `json:"show_correct_answers"`
+	AllowReview           bool   `json:"allow_review"`
+	ShuffleOptions        bool   `json:"shuffle_options"`
+	ScoringStrategy       string `json:"scoring_strategy" binding:"omitempty,oneof=flat linear kahoot streak negative"`
+	ScoringFloorPercent   int32  `json:"scoring_floor_percent" binding:"min=0,max=100"`
+	ScoringPenaltyPercent int32  `json:"scoring_penalty_percent" binding:"min=0,max=100"`
+	StreakBonusPercent    int32  `json:"streak_bonus_percent" binding:"min=0,max=100"`
+	StreakBonusMaxPercent int32  `json:"streak_bonus_max_percent" binding:"min=0,max=1000"`
+	LeaderboardRanking    string `json:"leaderboard_ranking" binding:"omitempty,oneof=competition dense"`
 }
 
 type QuestionInput struct {
 	ID            string   `json:"id"`
 	Text          string   `json:"text" binding:"required"`
-	Type          string   `json:"type" binding:"required,oneof=open multiple_choice"`
+	Type          string   `json:"type" binding:"required,oneof=open multiple_choice multi_select ordering numeric true_false matching"`
 	Options       []string `json:"options"`
-	CorrectAnswer string   `json:"correct_answer" binding:"required"`
+	CorrectAnswer string   `json:"correct_answer" binding:"required"` // JSON for multi_select, ordering, numeric, true_false and matching
 	OrderIndex    int32    `json:"order_index"`
 	MaxScore      int32    `json:"max_score" binding:"required"`
 	TimeLimitSec  int32    `json:"time_limit_sec"`
@@ -72,7 +79,8 @@ type CreateInstanceRequest struct {
 	TemplateID string `json:"template_id" binding:"required"`
 	Title      string `json:"title" binding:"required"`
 	GroupID    string `json:"group_id"`
-	Deadline   string `json:"deadline"` // ISO 8601 format
+	StartTime  string `json:"start_time"` // ISO 8601 format, async quizzes open automatically at this time
+	Deadline   string `json:"deadline"`   // ISO 8601 format
 }
 
 type InstanceDTO struct {
@@ -86,6 +94,7 @@ type InstanceDTO struct {
 	QuizType   string       `json:"quiz_type"`
 	Settings   QuizSettings `json:"settings"`
 	CreatedAt  string       `json:"created_at"`
+	StartTime  string       `json:"start_time,omitempty"`
 	Deadline   string       `json:"deadline,omitempty"`
 }
 
@@ -100,8 +109,40 @@ type GetInstanceResponse struct {
 	Questions []QuestionDTO `json:"questions"`
 }
 
+type ParticipationDTO struct {
+	Instance      InstanceDTO `json:"instance"`
+	SessionStatus string      `json:"session_status"`
+	Score         int32       `json:"score"`
+	StartedAt     string      `json:"started_at"`
+	FinishedAt    string      `json:"finished_at,omitempty"`
+}
+
 type GetParticipatingInstancesResponse struct {
-	Instances []InstanceDTO `json:"instances"`
+	Participations []ParticipationDTO `json:"participations"`
+	Total          int32              `json:"total"`
+}
+
+type AvailableInstanceDTO struct {
+	Instance      InstanceDTO `json:"instance"`
+	GroupName     string      `json:"group_name"`
+	SessionStatus string      `json:"session_status,omitempty"`
+	Score         int32       `json:"score"`
+}
+
+type GetAvailableInstancesResponse struct {
+	Instances []AvailableInstanceDTO `json:"instances"`
+}
+
+type GetParticipantResultResponse struct {
+	Participation  ParticipationDTO `json:"participation"`
+	Rank           int32            `json:"rank"`
+	Participants   int32            `json:"participants"`
+	MaxScore       int32            `json:"max_score"`
+	TotalQuestions int32            `json:"total_questions"`
+	Answered       int32            `json:"answered"`
+	Correct        int32            `json:"correct"`
+	PendingReview  int32            `json:"pending_review"`
+	Final          bool             `json:"final"`
 }
 
 type GetHostingInstancesResponse struct {
@@ -133,18 +174,98 @@ type GetResultsResponse struct {
 	Results    []UserResultDTO `json:"results"`
 }
 
+type ReviewItemDTO struct {
+	Question      QuestionDTO `json:"question"`
+	Answered      bool        `json:"answered"`
+	Answer        string      `json:"answer,omitempty"`
+	IsCorrect     bool        `json:"is_correct"`
+	Score         int32       `json:"score"`
+	TimeSpentMs   int64       `json:"time_spent_ms"`
+	CorrectAnswer string      `json:"correct_answer,omitempty"`
+}
+
+type GetReviewResponse struct {
+	Instance              InstanceDTO     `json:"instance"`
+	Items                 []ReviewItemDTO `json:"items"`
+	TotalScore            int32           `json:"total_score"`
+	CorrectAnswersVisible bool            `json:"correct_answers_visible"`
+}
+
+type UngradedAnswerDTO struct {
+	UserID        string `json:"user_id"`
+	QuestionID    string `json:"question_id"`
+	QuestionText  string `json:"question_text"`
+	Answer        string `json:"answer"`
+	MaxScore      int32  `json:"max_score"`
+	CorrectAnswer string `json:"correct_answer,omitempty"`
+	SubmittedAt   string `json:"submitted_at,omitempty"`
+
+	AISuggestion *AISuggestionDTO `json:"ai_suggestion,omitempty"`
+}
+
+type AISuggestionDTO struct {
+	Similarity     float64 `json:"similarity"`
+	SuggestedScore int32   `json:"suggested_score"`
+	Model          string  `json:"model"`
+}
+
+type GetUngradedAnswersResponse struct {
+	Answers []UngradedAnswerDTO `json:"answers"`
+}
+
 type GradeAnswerRequest struct {
 	UserID     string `json:"user_id" binding:"required"`
 	QuestionID string `json:"question_id" binding:"required"`
-	Score      int32  `json:"score" binding:"required"`
+	Score      int32  `json:"score" binding:"min=0"`
+	Feedback   string `json:"feedback"`
 }
 
 type GradeAnswerResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	TotalScore int32  `json:"total_score"`
 }
 
 type PublishResultsResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+}
+
+type OptionAnalyticsDTO struct {
+	Index      int32  `json:"index"`
+	Text       string `json:"text"`
+	IsCorrect  bool   `json:"is_correct"`
+	Count      int32  `json:"count"`
+	UpperCount int32  `json:"upper_count"`
+	LowerCount int32  `json:"lower_count"`
+}
+
+type QuestionAnalyticsDTO struct {
+	QuestionID     string               `json:"question_id"`
+	QuestionText   string               `json:"question_text"`
+	QuestionType   string               `json:"question_type"`
+	MaxScore       int32                `json:"max_score"`
+	Answered       int32                `json:"answered"`
+	PendingReview  int32                `json:"pending_review"`
+	Difficulty     float64              `json:"difficulty"`
+	Discrimination float64              `json:"discrimination"`
+	MeanTimeMs     float64              `json:"mean_time_ms"`
+	Options        []OptionAnalyticsDTO `json:"options,omitempty"`
+}
+
+type ScoreBucketDTO struct {
+	MinPercent float64 `json:"min_percent"`
+	MaxPercent float64 `json:"max_percent"`
+	Count      int32   `json:"count"`
+}
+
+type GetInstanceAnalyticsResponse struct {
+	Participants      int32                  `json:"participants"`
+	MaxScore          int32                  `json:"max_score"`
+	MeanScore         float64                `json:"mean_score"`
+	MedianScore       float64                `json:"median_score"`
+	MeanTimeMs        float64                `json:"mean_time_ms"`
+	MedianTimeMs      float64                `json:"median_time_ms"`
+	ScoreDistribution []ScoreBucketDTO       `json:"score_distribution"`
+	Questions         []QuestionAnalyticsDTO `json:"questions"`
 }
\ No newline at end of file
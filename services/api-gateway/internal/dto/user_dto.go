@@ -16,6 +16,8 @@ type UpdateNotificationSettingsRequest struct {
 	QuizResults      *bool   `json:"quiz_results,omitempty" example:"true"`
 	GroupInvites     *bool   `json:"group_invites,omitempty" example:"true"`
 	DeadlineReminder *string `json:"deadline_reminder,omitempty" example:"24h"`
+	EmailEnabled     *bool   `json:"email_enabled,omitempty" example:"true"`
+	InAppEnabled     *bool   `json:"in_app_enabled,omitempty" example:"true"`
 }
 
 
@@ -46,6 +48,8 @@ type NotificationSettingsDTO struct {
 	QuizResults      bool   `json:"quiz_results" example:"true"`
 	GroupInvites     bool   `json:"group_invites" example:"true"`
 	DeadlineReminder string `json:"deadline_reminder" example:"24h"`
+	EmailEnabled     bool   `json:"email_enabled" example:"true"`
+	InAppEnabled     bool   `json:"in_app_enabled" example:"true"`
 }
 
 
@@ -0,0 +1,73 @@
+// Package export writes tabular results as CSV or XLSX files.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Table is a header row followed by data rows. Cells are strings or numbers;
+// XLSX keeps numbers numeric so they can be summed in a spreadsheet.
+type Table struct {
+	Header []string
+	Rows   [][]any
+}
+
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Write writes the table in the given format.
+func Write(w io.Writer, format string, table *Table) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, table)
+	case FormatXLSX:
+		return WriteXLSX(w, table)
+	}
+	return fmt.Errorf("unknown export format: %s", format)
+}
+
+func WriteCSV(w io.Writer, table *Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(table.Header); err != nil {
+		return err
+	}
+
+	record := make([]string, 0, len(table.Header))
+	for _, row := range table.Rows {
+		record = record[:0]
+		for _, cell := range row {
+			record = append(record, csvCell(cell))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell formats a CSV cell. Text that a spreadsheet would run as a formula
+// is prefixed with a quote so it is shown as typed; numbers are kept as is.
+func csvCell(cell any) string {
+	text, ok := cell.(string)
+	if !ok {
+		return fmt.Sprint(cell)
+	}
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+var testTable = &Table{
+	Header: []string{"Name", "Score"},
+	Rows: [][]any{
+		{"Ada, \"the first\"", int32(10)},
+		{"<Bob> & co", int64(7)},
+	},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, testTable); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Name,Score\n\"Ada, \"\"the first\"\"\",10\n<Bob> & co,7\n"
+	if buf.String() != want {
+		t.Fatalf("csv = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	formulas := &Table{
+		Header: []string{"Name", "Score"},
+		Rows: [][]any{
+			{"=HYPERLINK(\"x\")", -3},
+			{"+1", int32(0)},
+			{"-2", int64(-2)},
+			{"@SUM(A1)", 1},
+			{"\tcmd", 2},
+			{"\rcmd", 3},
+			{"a=b", 4},
+		},
+	}
+	if err := Write(&buf, FormatCSV, formulas); err != nil {
+		t.Fatal(err)
+	}
+
+	want = "Name,Score\n\"'=HYPERLINK(\"\"x\"\")\",-3\n'+1,0\n'-2,-2\n'@SUM(A1),1\n'\tcmd,2\n\"'\rcmd\",3\na=b,4\n"
+	if buf.String() != want {
+		t.Fatalf("csv = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatXLSX, testTable); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("not a zip file: %v", err)
+	}
+
+	var sheet string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+
+		if err := xml.Unmarshal(data, new(struct{})); err != nil {
+			t.Fatalf("%s is not valid XML: %v", f.Name, err)
+		}
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			sheet = string(data)
+		}
+	}
+
+	for _, want := range []string{
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">Ada, &#34;the first&#34;</t></is></c>`,
+		`<c r="B3"><v>7</v></c>`,
+		`&lt;Bob&gt; &amp; co`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Fatalf("sheet is missing %s:\n%s", want, sheet)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for col, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(col); got != want {
+			t.Fatalf("columnName(%d) = %s, want %s", col, got, want)
+		}
+	}
+}
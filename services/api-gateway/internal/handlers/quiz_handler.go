@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"api-gateway/internal/client"
 	"api-gateway/internal/dto"
+	"api-gateway/internal/export"
 	pb "api-gateway/proto"
 
 	"github.com/gin-gonic/gin"
@@ -58,13 +62,8 @@ func (h *QuizHandler) CreateTemplate(c *gin.Context) {
 		Title:       req.Title,
 		Description: req.Description,
 		QuizType:    req.QuizType,
-		Settings: &pb.QuizSettings{
-			RandomOrder:        req.Settings.RandomOrder,
-			TimeLimitTotal:     req.Settings.TimeLimitTotal,
-			ShowCorrectAnswers: req.Settings.ShowCorrectAnswers,
-			AllowReview:        req.Settings.AllowReview,
-		},
-		Questions: questions,
+		Settings:    settingsToProto(req.Settings),
+		Questions:   questions,
 	})
 
 	if err != nil {
@@ -72,6 +71,11 @@ func (h *QuizHandler) CreateTemplate(c *gin.Context) {
 		return
 	}
 
+	if resp.ErrorMessage != "" {
+		dto.JsonError(c, http.StatusBadRequest, resp.ErrorMessage)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.CreateTemplateResponse{
 		TemplateID: resp.Template.Id,
 		Message:    "Template created successfully",
@@ -121,15 +125,10 @@ func (h *QuizHandler) GetTemplates(c *gin.Context) {
 			Title:       t.Title,
 			Description: t.Description,
 			QuizType:    t.QuizType,
-			Settings: dto.QuizSettings{
-				RandomOrder:        t.Settings.RandomOrder,
-				TimeLimitTotal:     t.Settings.TimeLimitTotal,
-				ShowCorrectAnswers: t.Settings.ShowCorrectAnswers,
-				AllowReview:        t.Settings.AllowReview,
-			},
-			Questions: questions,
-			CreatedAt: t.CreatedAt.AsTime().Format(time.RFC3339),
-			UpdatedAt: t.UpdatedAt.AsTime().Format(time.RFC3339),
+			Settings:    settingsToDTO(t.Settings),
+			Questions:   questions,
+			CreatedAt:   t.CreatedAt.AsTime().Format(time.RFC3339),
+			UpdatedAt:   t.UpdatedAt.AsTime().Format(time.RFC3339),
 		}
 	}
 
@@ -181,15 +180,10 @@ func (h *QuizHandler) GetTemplate(c *gin.Context) {
 		Title:       t.Title,
 		Description: t.Description,
 		QuizType:    t.QuizType,
-		Settings: dto.QuizSettings{
-			RandomOrder:        t.Settings.RandomOrder,
-			TimeLimitTotal:     t.Settings.TimeLimitTotal,
-			ShowCorrectAnswers: t.Settings.ShowCorrectAnswers,
-			AllowReview:        t.Settings.AllowReview,
-		},
-		Questions: questions,
-		CreatedAt: t.CreatedAt.AsTime().Format(time.RFC3339),
-		UpdatedAt: t.UpdatedAt.AsTime().Format(time.RFC3339),
+		Settings:    settingsToDTO(t.Settings),
+		Questions:   questions,
+		CreatedAt:   t.CreatedAt.AsTime().Format(time.RFC3339),
+		UpdatedAt:   t.UpdatedAt.AsTime().Format(time.RFC3339),
 	}
 
 	c.JSON(http.StatusOK, dto.GetTemplateResponse{
@@ -237,13 +231,8 @@ func (h *QuizHandler) UpdateTemplate(c *gin.Context) {
 		Title:       req.Title,
 		Description: req.Description,
 		QuizType:    req.QuizType,
-		Settings: &pb.QuizSettings{
-			RandomOrder:        req.Settings.RandomOrder,
-			TimeLimitTotal:     req.Settings.TimeLimitTotal,
-			ShowCorrectAnswers: req.Settings.ShowCorrectAnswers,
-			AllowReview:        req.Settings.AllowReview,
-		},
-		Questions: questions,
+		Settings:    settingsToProto(req.Settings),
+		Questions:   questions,
 	})
 
 	if err != nil {
@@ -251,6 +240,11 @@ func (h *QuizHandler) UpdateTemplate(c *gin.Context) {
 		return
 	}
 
+	if resp.ErrorMessage != "" {
+		dto.JsonError(c, http.StatusBadRequest, resp.ErrorMessage)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.CreateTemplateResponse{
 		TemplateID: resp.Template.Id,
 		Message:    "Template updated successfully",
@@ -310,6 +304,15 @@ func (h *QuizHandler) CreateInstance(c *gin.Context) {
 		GroupId:    req.GroupID,
 	}
 
+	if req.StartTime != "" {
+		startTime, err := time.Parse(time.RFC3339, req.StartTime)
+		if err != nil {
+			dto.JsonError(c, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		protoReq.StartTime = timestamppb.New(startTime)
+	}
+
 	if req.Deadline != "" {
 		deadline, err := time.Parse(time.RFC3339, req.Deadline)
 		if err != nil {
@@ -326,6 +329,11 @@ func (h *QuizHandler) CreateInstance(c *gin.Context) {
 		return
 	}
 
+	if resp.ErrorMessage != "" {
+		dto.JsonError(c, http.StatusBadRequest, resp.ErrorMessage)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.CreateInstanceResponse{
 		InstanceID: resp.Instance.Id,
 		AccessCode: resp.Instance.AccessCode,
@@ -355,28 +363,7 @@ func (h *QuizHandler) GetInstance(c *gin.Context) {
 		return
 	}
 
-	inst := resp.Instance
-	instance := dto.InstanceDTO{
-		ID:         inst.Id,
-		TemplateID: inst.TemplateId,
-		HostUserID: inst.CreatedBy,
-		Title:      inst.Title,
-		AccessCode: inst.AccessCode,
-		GroupID:    inst.GroupId,
-		Status:     inst.Status,
-		QuizType:   inst.QuizType,
-		Settings: dto.QuizSettings{
-			RandomOrder:        inst.Settings.RandomOrder,
-			TimeLimitTotal:     inst.Settings.TimeLimitTotal,
-			ShowCorrectAnswers: inst.Settings.ShowCorrectAnswers,
-			AllowReview:        inst.Settings.AllowReview,
-		},
-		CreatedAt: inst.CreatedAt.AsTime().Format(time.RFC3339),
-	}
-
-	if inst.Deadline != nil {
-		instance.Deadline = inst.Deadline.AsTime().Format(time.RFC3339)
-	}
+	instance := instanceToDTO(resp.Instance)
 
 	questions := make([]dto.QuestionDTO, len(resp.Questions))
 	for i, q := range resp.Questions {
@@ -422,30 +409,592 @@ func (h *QuizHandler) GetHostingInstances(c *gin.Context) {
 
 	instances := make([]dto.InstanceDTO, len(resp.Instances))
 	for i, inst := range resp.Instances {
-		instances[i] = dto.InstanceDTO{
-			ID:         inst.Id,
-			TemplateID: inst.TemplateId,
-			HostUserID: inst.CreatedBy,
-			Title:      inst.Title,
-			AccessCode: inst.AccessCode,
-			GroupID:    inst.GroupId,
-			Status:     inst.Status,
-			QuizType:   inst.QuizType,
-			Settings: dto.QuizSettings{
-				RandomOrder:        inst.Settings.RandomOrder,
-				TimeLimitTotal:     inst.Settings.TimeLimitTotal,
-				ShowCorrectAnswers: inst.Settings.ShowCorrectAnswers,
-				AllowReview:        inst.Settings.AllowReview,
-			},
-			CreatedAt: inst.CreatedAt.AsTime().Format(time.RFC3339),
+		instances[i] = instanceToDTO(inst)
+	}
+
+	c.JSON(http.StatusOK, dto.GetHostingInstancesResponse{
+		Instances: instances,
+	})
+}
+
+// GetParticipatingInstances godoc
+// @Summary Get quizzes the user took part in
+// @Tags Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Instance status filter"
+// @Param group_id query string false "Group filter"
+// @Param from query string false "Joined on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Joined before (RFC 3339), or on (YYYY-MM-DD)"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} dto.GetParticipatingInstancesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /quizzes/instances/participating [get]
+func (h *QuizHandler) GetParticipatingInstances(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	req := &pb.GetParticipatingInstancesRequest{
+		UserId:  userID,
+		Status:  c.Query("status"),
+		GroupId: c.Query("group_id"),
+		Limit:   int32(limit),
+		Offset:  int32(offset),
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, _, err := parseDateParam(from)
+		if err != nil {
+			dto.JsonError(c, http.StatusBadRequest, "Invalid from date")
+			return
 		}
+		req.From = timestamppb.New(t)
+	}
+	if to := c.Query("to"); to != "" {
+		t, dateOnly, err := parseDateParam(to)
+		if err != nil {
+			dto.JsonError(c, http.StatusBadRequest, "Invalid to date")
+			return
+		}
+		if dateOnly {
+			// A plain date includes the whole day.
+			t = t.AddDate(0, 0, 1)
+		}
+		req.To = timestamppb.New(t)
+	}
+
+	resp, err := h.quizClient.GetParticipatingInstances(c.Request.Context(), req)
+	if err != nil {
+		dto.JsonError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	participations := make([]dto.ParticipationDTO, len(resp.Participations))
+	for i, p := range resp.Participations {
+		participations[i] = participationToDTO(p)
+	}
 
-		if inst.Deadline != nil {
-			instances[i].Deadline = inst.Deadline.AsTime().Format(time.RFC3339)
+	c.JSON(http.StatusOK, dto.GetParticipatingInstancesResponse{
+		Participations: participations,
+		Total:          resp.Total,
+	})
+}
+
+// GetAvailableInstances godoc
+// @Summary Get async quizzes assigned to the user's groups
+// @Tags Quiz
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.GetAvailableInstancesResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /quizzes/instances/available [get]
+func (h *QuizHandler) GetAvailableInstances(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	resp, err := h.quizClient.GetAvailableInstances(c.Request.Context(), &pb.GetAvailableInstancesRequest{
+		UserId: userID,
+	})
+
+	if err != nil {
+		dto.JsonError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !resp.Success {
+		dto.JsonError(c, http.StatusInternalServerError, resp.Message)
+		return
+	}
+
+	instances := make([]dto.AvailableInstanceDTO, len(resp.Instances))
+	for i, inst := range resp.Instances {
+		instances[i] = dto.AvailableInstanceDTO{
+			Instance:      instanceToDTO(inst.Instance),
+			GroupName:     inst.GroupName,
+			SessionStatus: inst.SessionStatus,
+			Score:         inst.Score,
 		}
 	}
 
-	c.JSON(http.StatusOK, dto.GetHostingInstancesResponse{
+	c.JSON(http.StatusOK, dto.GetAvailableInstancesResponse{
 		Instances: instances,
 	})
 }
+
+// GetParticipantResult godoc
+// @Summary Get the user's own result in a quiz
+// @Tags Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Instance ID"
+// @Success 200 {object} dto.GetParticipantResultResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /quizzes/instances/{id}/result [get]
+func (h *QuizHandler) GetParticipantResult(c *gin.Context) {
+	userID := c.GetString("user_id")
+	instanceID := c.Param("id")
+
+	resp, err := h.quizClient.GetParticipantResult(c.Request.Context(), &pb.GetParticipantResultRequest{
+		InstanceId: instanceID,
+		UserId:     userID,
+	})
+
+	if err != nil {
+		dto.JsonError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !resp.HasAccess {
+		dto.JsonError(c, http.StatusForbidden, resp.ErrorMessage)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetParticipantResultResponse{
+		Participation:  participationToDTO(resp.Participation),
+		Rank:           resp.Rank,
+		Participants:   resp.Participants,
+		MaxScore:       resp.MaxScore,
+		TotalQuestions: resp.TotalQuestions,
+		Answered:       resp.Answered,
+		Correct:        resp.Correct,
+		PendingReview:  resp.PendingReview,
+		Final:          resp.Final,
+	})
+}
+
+// parseDateParam accepts an RFC 3339 time or a plain date, and reports which
+// one it got.
+func parseDateParam(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	return t, true, err
+}
+
+func participationToDTO(p *pb.Participation) dto.ParticipationDTO {
+	participation := dto.ParticipationDTO{
+		Instance:      instanceToDTO(p.Instance),
+		SessionStatus: p.SessionStatus,
+		Score:         p.Score,
+		StartedAt:     p.StartedAt.AsTime().Format(time.RFC3339),
+	}
+	if p.FinishedAt != nil {
+		participation.FinishedAt = p.FinishedAt.AsTime().Format(time.RFC3339)
+	}
+	return participation
+}
+
+// GetReview godoc
+// @Summary Review own answers of a quiz instance
+// @Description Correct answers are included only when the quiz shows them and the instance is finished
+// @Tags Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Instance ID"
+// @Success 200 {object} dto.GetReviewResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /quizzes/instances/{id}/review [get]
+func (h *QuizHandler) GetReview(c *gin.Context) {
+	userID := c.GetString("user_id")
+	instanceID := c.Param("id")
+
+	resp, err := h.quizClient.GetReview(c.Request.Context(), &pb.GetReviewRequest{
+		InstanceId: instanceID,
+		UserId:     userID,
+	})
+
+	if err != nil {
+		dto.JsonError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !resp.HasAccess {
+		dto.JsonError(c, http.StatusForbidden, resp.ErrorMessage)
+		return
+	}
+
+	items := make([]dto.ReviewItemDTO, len(resp.Items))
+	for i, item := range resp.Items {
+		q := item.Question
+		items[i] = dto.ReviewItemDTO{
+			Question: dto.QuestionDTO{
+				ID:           q.Id,
+				Text:         q.Text,
+				Type:         q.Type,
+				Options:      q.Options,
+				OrderIndex:   q.OrderIndex,
+				MaxScore:     q.MaxScore,
+				TimeLimitSec: q.TimeLimitSec,
+			},
+			Answered:      item.Answered,
+			Answer:        item.Answer,
+			IsCorrect:     item.IsCorrect,
+			Score:         item.Score,
+			TimeSpentMs:   item.TimeSpentMs,
+			CorrectAnswer: item.CorrectAnswer,
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.GetReviewResponse{
+		Instance:              instanceToDTO(resp.Instance),
+		Items:                 items,
+		TotalScore:            resp.TotalScore,
+		CorrectAnswersVisible: resp.CorrectAnswersVisible,
+	})
+}
+
+// GetUngradedAnswers godoc
+// @Summary List answers awaiting manual grading
+// @Tags Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Instance ID"
+// @Success 200 {object} dto.GetUngradedAnswersResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /quizzes/instances/{id}/answers/ungraded [get]
+func (h *QuizHandler) GetUngradedAnswers(c *gin.Context) {
+	userID := c.GetString("user_id")
+	instanceID := c.Param("id")
+
+	resp, err := h.quizClient.GetUngradedAnswers(c.Request.Context(), &pb.GetUngradedAnswersRequest{
+		InstanceId: instanceID,
+		UserId:     userID,
+	})
+
+	if err != nil {
+		dto.JsonError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !resp.HasAccess {
+		dto.JsonError(c, http.StatusForbidden, resp.ErrorMessage)
+		return
+	}
+
+	answers := make([]dto.UngradedAnswerDTO, len(resp.Answers))
+	for i, a := range resp.Answers {
+		answers[i] = dto.UngradedAnswerDTO{
+			UserID:        a.UserId,
+			QuestionID:    a.QuestionId,
+			QuestionText:  a.QuestionText,
+			Answer:        a.Answer,
+			MaxScore:      a.MaxScore,
+			CorrectAnswer: a.CorrectAnswer,
+			SubmittedAt:   a.SubmittedAt,
+		}
+		if a.AiSuggestion != nil {
+			answers[i].AISuggestion = &dto.AISuggestionDTO{
+				Similarity:     a.AiSuggestion.Similarity,
+				SuggestedScore: a.AiSuggestion.SuggestedScore,
+				Model:          a.AiSuggestion.Model,
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.GetUngradedAnswersResponse{
+		Answers: answers,
+	})
+}
+
+// GradeAnswer godoc
+// @Summary Grade a participant's answer
+// @Tags Quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Instance ID"
+// @Param request body dto.GradeAnswerRequest true "Grade"
+// @Success 200 {object} dto.GradeAnswerResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /quizzes/instances/{id}/grade [post]
+func (h *QuizHandler) GradeAnswer(c *gin.Context) {
+	userID := c.GetString("user_id")
+	instanceID := c.Param("id")
+
+	var req dto.GradeAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dto.JsonError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	resp, err := h.quizClient.GradeAnswer(c.Request.Context(), &pb.GradeAnswerRequest{
+		InstanceId: instanceID,
+		HostUserId: userID,
+		UserId:     req.UserID,
+		QuestionId: req.QuestionID,
+		Score:      req.Score,
+		Feedback:   req.Feedback,
+	})
+
+	if err != nil {
+		dto.JsonError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !resp.Success {
+		dto.JsonError(c, http.StatusBadRequest, resp.Message)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GradeAnswerResponse{
+		Success:    resp.Success,
+		Message:    resp.Message,
+		TotalScore: resp.TotalScore,
+	})
+}
+
+// PublishResults godoc
+// @Summary Publish results after manual grading
+// @Tags Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Instance ID"
+// @Success 200 {object} dto.PublishResultsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /quizzes/instances/{id}/publish [post]
+func (h *QuizHandler) PublishResults(c *gin.Context) {
+	userID := c.GetString("user_id")
+	instanceID := c.Param("id")
+
+	resp, err := h.quizClient.PublishResults(c.Request.Context(), &pb.PublishResultsRequest{
+		InstanceId: instanceID,
+		UserId:     userID,
+	})
+
+	if err != nil {
+		dto.JsonError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !resp.Success {
+		dto.JsonError(c, http.StatusBadRequest, resp.Message)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PublishResultsResponse{
+		Success: resp.Success,
+		Message: resp.Message,
+	})
+}
+
+func instanceToDTO(inst *pb.QuizInstance) dto.InstanceDTO {
+	instance := dto.InstanceDTO{
+		ID:         inst.Id,
+		TemplateID: inst.TemplateId,
+		HostUserID: inst.CreatedBy,
+		Title:      inst.Title,
+		AccessCode: inst.AccessCode,
+		GroupID:    inst.GroupId,
+		Status:     inst.Status,
+		QuizType:   inst.QuizType,
+		Settings:   settingsToDTO(inst.Settings),
+		CreatedAt:  inst.CreatedAt.AsTime().Format(time.RFC3339),
+	}
+
+	if inst.StartTime != nil {
+		instance.StartTime = inst.StartTime.AsTime().Format(time.RFC3339)
+	}
+
+	if inst.Deadline != nil {
+		instance.Deadline = inst.Deadline.AsTime().Format(time.RFC3339)
+	}
+
+	return instance
+}
+
+func settingsToDTO(settings *pb.QuizSettings) dto.QuizSettings {
+	return dto.QuizSettings{
+		RandomOrder:           settings.GetRandomOrder(),
+		TimeLimitTotal:        settings.GetTimeLimitTotal(),
+		ShowCorrectAnswers:    settings.GetShowCorrectAnswers(),
+		AllowReview:           settings.GetAllowReview(),
+		ShuffleOptions:        settings.GetShuffleOptions(),
+		ScoringStrategy:       settings.GetScoringStrategy(),
+		ScoringFloorPercent:   settings.GetScoringFloorPercent(),
+		ScoringPenaltyPercent: settings.GetScoringPenaltyPercent(),
+		StreakBonusPercent:    settings.GetStreakBonusPercent(),
+		StreakBonusMaxPercent: settings.GetStreakBonusMaxPercent(),
+		LeaderboardRanking:    settings.GetLeaderboardRanking(),
+	}
+}
+
+func settingsToProto(settings dto.QuizSettings) *pb.QuizSettings {
+	return &pb.QuizSettings{
+		RandomOrder:           settings.RandomOrder,
+		TimeLimitTotal:        settings.TimeLimitTotal,
+		ShowCorrectAnswers:    settings.ShowCorrectAnswers,
+		AllowReview:           settings.AllowReview,
+		ShuffleOptions:        settings.ShuffleOptions,
+		ScoringStrategy:       settings.ScoringStrategy,
+		ScoringFloorPercent:   settings.ScoringFloorPercent,
+		ScoringPenaltyPercent: settings.ScoringPenaltyPercent,
+		StreakBonusPercent:    settings.StreakBonusPercent,
+		StreakBonusMaxPercent: settings.StreakBonusMaxPercent,
+		LeaderboardRanking:    settings.LeaderboardRanking,
+	}
+}
+
+// GetInstanceAnalytics godoc
+// @Summary Get item analysis of a quiz instance
+// @Tags Quiz
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Instance ID"
+// @Success 200 {object} dto.GetInstanceAnalyticsResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /quizzes/instances/{id}/analytics [get]
+func (h *QuizHandler) GetInstanceAnalytics(c *gin.Context) {
+	userID := c.GetString("user_id")
+	instanceID := c.Param("id")
+
+	resp, err := h.quizClient.GetInstanceAnalytics(c.Request.Context(), &pb.GetInstanceAnalyticsRequest{
+		InstanceId: instanceID,
+		UserId:     userID,
+	})
+
+	if err != nil {
+		dto.JsonError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !resp.HasAccess {
+		dto.JsonError(c, http.StatusForbidden, resp.ErrorMessage)
+		return
+	}
+
+	a := resp.Analytics
+	buckets := make([]dto.ScoreBucketDTO, len(a.ScoreDistribution))
+	for i, b := range a.ScoreDistribution {
+		buckets[i] = dto.ScoreBucketDTO{
+			MinPercent: b.MinPercent,
+			MaxPercent: b.MaxPercent,
+			Count:      b.Count,
+		}
+	}
+
+	questions := make([]dto.QuestionAnalyticsDTO, len(a.Questions))
+	for i, q := range a.Questions {
+		questions[i] = dto.QuestionAnalyticsDTO{
+			QuestionID:     q.QuestionId,
+			QuestionText:   q.QuestionText,
+			QuestionType:   q.QuestionType,
+			MaxScore:       q.MaxScore,
+			Answered:       q.Answered,
+			PendingReview:  q.PendingReview,
+			Difficulty:     q.Difficulty,
+			Discrimination: q.Discrimination,
+			MeanTimeMs:     q.MeanTimeMs,
+		}
+		for _, o := range q.Options {
+			questions[i].Options = append(questions[i].Options, dto.OptionAnalyticsDTO{
+				Index:      o.Index,
+				Text:       o.Text,
+				IsCorrect:  o.IsCorrect,
+				Count:      o.Count,
+				UpperCount: o.UpperCount,
+				LowerCount: o.LowerCount,
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.GetInstanceAnalyticsResponse{
+		Participants:      a.Participants,
+		MaxScore:          a.MaxScore,
+		MeanScore:         a.MeanScore,
+		MedianScore:       a.MedianScore,
+		MeanTimeMs:        a.MeanTimeMs,
+		MedianTimeMs:      a.MedianTimeMs,
+		ScoreDistribution: buckets,
+		Questions:         questions,
+	})
+}
+
+// ExportResults godoc
+// @Summary Export quiz results as CSV or XLSX
+// @Tags Quiz
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param id path string true "Instance ID"
+// @Param format query string false "csv (default) or xlsx"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /quizzes/instances/{id}/export [get]
+func (h *QuizHandler) ExportResults(c *gin.Context) {
+	userID := c.GetString("user_id")
+	instanceID := c.Param("id")
+
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		dto.JsonError(c, http.StatusBadRequest, "Format must be csv or xlsx")
+		return
+	}
+
+	resp, err := h.quizClient.GetInstanceResults(c.Request.Context(), &pb.GetInstanceResultsRequest{
+		InstanceId: instanceID,
+		UserId:     userID,
+	})
+
+	if err != nil {
+		dto.JsonError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !resp.HasAccess {
+		dto.JsonError(c, http.StatusForbidden, resp.ErrorMessage)
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="results-%s.%s"`, instanceID, format))
+	c.Status(http.StatusOK)
+
+	if err := export.Write(c.Writer, format, resultsTable(resp)); err != nil {
+		log.Printf("Failed to export results of instance %s: %v", instanceID, err)
+	}
+}
+
+// resultsTable lays out one row per participant with the answer, score and
+// time of every question.
+func resultsTable(resp *pb.GetInstanceResultsResponse) *export.Table {
+	table := &export.Table{
+		Header: []string{"Rank", "First name", "Last name", "Email", "Score", "Finished at"},
+	}
+	for i := range resp.Questions {
+		table.Header = append(table.Header,
+			fmt.Sprintf("Q%d answer", i+1),
+			fmt.Sprintf("Q%d score", i+1),
+			fmt.Sprintf("Q%d time (ms)", i+1),
+		)
+	}
+
+	for _, r := range resp.Results {
+		finishedAt := ""
+		if r.FinishedAt != nil {
+			finishedAt = r.FinishedAt.AsTime().Format(time.RFC3339)
+		}
+
+		row := []any{r.Rank, r.FirstName, r.LastName, r.Email, r.Score, finishedAt}
+		for _, a := range r.Answers {
+			if !a.Answered {
+				row = append(row, "", "", "")
+				continue
+			}
+			row = append(row, a.Answer, a.Score, a.TimeSpentMs)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
@@ -274,6 +274,8 @@ func (h *UserHandler) UpdateNotificationSettings(c *gin.Context) {
 		QuizResults:      req.QuizResults,
 		GroupInvites:     req.GroupInvites,
 		DeadlineReminder: req.DeadlineReminder,
+		EmailEnabled:     req.EmailEnabled,
+		InAppEnabled:     req.InAppEnabled,
 	}
 
 	resp, err := h.userClient.UpdateNotificationSettings(ctx, updateReq)
@@ -531,6 +533,8 @@ func convertNotificationSettingsToDTO(s *pb.NotificationSettings) dto.Notificati
 		QuizResults:      s.QuizResults,
 		GroupInvites:     s.GroupInvites,
 		DeadlineReminder: s.DeadlineReminder,
+		EmailEnabled:     s.EmailEnabled,
+		InAppEnabled:     s.InAppEnabled,
 	}
 }
 
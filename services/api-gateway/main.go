@@ -128,7 +128,16 @@ func main() {
 
 		quizzesGroup.POST("/instances", quizHandler.CreateInstance)
 		quizzesGroup.GET("/instances/hosting", quizHandler.GetHostingInstances)
+		quizzesGroup.GET("/instances/participating", quizHandler.GetParticipatingInstances)
+		quizzesGroup.GET("/instances/available", quizHandler.GetAvailableInstances)
 		quizzesGroup.GET("/instances/:id", quizHandler.GetInstance)
+		quizzesGroup.GET("/instances/:id/review", quizHandler.GetReview)
+		quizzesGroup.GET("/instances/:id/result", quizHandler.GetParticipantResult)
+		quizzesGroup.GET("/instances/:id/answers/ungraded", quizHandler.GetUngradedAnswers)
+		quizzesGroup.POST("/instances/:id/grade", quizHandler.GradeAnswer)
+		quizzesGroup.POST("/instances/:id/publish", quizHandler.PublishResults)
+		quizzesGroup.GET("/instances/:id/analytics", quizHandler.GetInstanceAnalytics)
+		quizzesGroup.GET("/instances/:id/export", quizHandler.ExportResults)
 	}
 
 	notificationsGroup := router.Group("/notifications")
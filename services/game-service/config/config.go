@@ -9,7 +9,9 @@ type Config struct {
 	Server   ServerConfig
 	DB       DBConfig
 	Redis    RedisConfig
+	RabbitMQ RabbitMQConfig
 	Quiz     QuizServiceConfig
+	User     UserServiceConfig
 	Auth     AuthServiceConfig
 }
 
@@ -34,11 +36,23 @@ type RedisConfig struct {
 	DB       int
 }
 
+type RabbitMQConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+}
+
 type QuizServiceConfig struct {
 	Host string
 	Port string
 }
 
+type UserServiceConfig struct {
+	Host string
+	Port string
+}
+
 type AuthServiceConfig struct {
 	Host string
 	Port string
@@ -64,10 +78,20 @@ func Load() *Config {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
+		RabbitMQ: RabbitMQConfig{
+			Host:     getEnv("RABBITMQ_HOST", "rabbitmq"),
+			Port:     getEnv("RABBITMQ_PORT", "5672"),
+			User:     getEnv("RABBITMQ_USER", "admin"),
+			Password: getEnv("RABBITMQ_PASSWORD", "admin"),
+		},
 		Quiz: QuizServiceConfig{
 			Host: getEnv("QUIZ_SERVICE_HOST", "localhost"),
 			Port: getEnv("QUIZ_SERVICE_PORT", "50051"),
 		},
+		User: UserServiceConfig{
+			Host: getEnv("USER_SERVICE_HOST", "localhost"),
+			Port: getEnv("USER_SERVICE_PORT", "50051"),
+		},
 		Auth: AuthServiceConfig{
 			Host: getEnv("AUTH_SERVICE_HOST", "localhost"),
 			Port: getEnv("AUTH_SERVICE_PORT", "50051"),
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	pb "game-service/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type UserClient struct {
+	conn   *grpc.ClientConn
+	client pb.UserServiceClient
+}
+
+func NewUserClient(host, port string) (*UserClient, error) {
+	addr := fmt.Sprintf("%s:%s", host, port)
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to user service: %w", err)
+	}
+
+	return &UserClient{
+		conn:   conn,
+		client: pb.NewUserServiceClient(conn),
+	}, nil
+}
+
+func (c *UserClient) Close() error {
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// GetUsersByIDs returns the users found for the given ids, keyed by id.
+func (c *UserClient) GetUsersByIDs(ctx context.Context, userIDs []string) (map[string]*pb.User, error) {
+	resp, err := c.client.GetUsersByIDs(ctx, &pb.GetUsersByIDsRequest{UserIds: userIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get users: %s", resp.Message)
+	}
+
+	users := make(map[string]*pb.User, len(resp.Users))
+	for _, u := range resp.Users {
+		users[u.Id] = u
+	}
+	return users, nil
+}
+
+// GetGroupMembers returns a group's name and member ids. requesterID must be
+// the group's owner or a member.
+func (c *UserClient) GetGroupMembers(ctx context.Context, groupID, requesterID string) (string, []string, error) {
+	resp, err := c.client.GetGroup(ctx, &pb.GetGroupRequest{
+		GroupId: groupID,
+		UserId:  requesterID,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	if !resp.Success {
+		return "", nil, fmt.Errorf("failed to get group %s: %s", groupID, resp.Message)
+	}
+
+	userIDs := make([]string, len(resp.Group.Members))
+	for i, member := range resp.Group.Members {
+		userIDs[i] = member.Id
+	}
+	return resp.Group.Group.Name, userIDs, nil
+}
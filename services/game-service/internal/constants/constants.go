@@ -4,12 +4,16 @@ const (
 	InstanceStatusWaiting  = "waiting"
 	InstanceStatusActive   = "active"
 	InstanceStatusFinished = "finished"
+
+	InstanceStatusPendingReview = "pending_review"
+	InstanceStatusReviewed      = "reviewed"
 )
 
 const (
 	SessionStatusJoined     = "joined"
 	SessionStatusInProgress = "in_progress"
 	SessionStatusFinished   = "finished"
+	SessionStatusKicked     = "kicked"
 )
 
 const (
@@ -17,7 +21,47 @@ const (
 	QuizTypeAsync = "async"
 )
 
+const (
+	QuestionTypeOpen           = "open"
+	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeMultiSelect    = "multi_select"
+	QuestionTypeOrdering       = "ordering"
+	QuestionTypeNumeric        = "numeric"
+	QuestionTypeTrueFalse      = "true_false"
+	QuestionTypeMatching       = "matching"
+)
+
+const (
+	ScoringFlat     = "flat"
+	ScoringLinear   = "linear"
+	ScoringKahoot   = "kahoot"
+	ScoringStreak   = "streak"
+	ScoringNegative = "negative"
+)
+
+const (
+	RankingCompetition = "competition"
+	RankingDense       = "dense"
+)
+
+const (
+	TeamModeManual = "manual"
+	TeamModeRandom = "random"
+	TeamModeGroups = "groups"
+)
+
+const (
+	RoleParticipant = "participant"
+	RoleSpectator   = "spectator"
+)
+
 const (
 	ActionJoined = "joined"
 	ActionLeft   = "left"
+)
+
+const (
+	EndReasonHost      = "ended_by_host"
+	EndReasonTimeLimit = "time_limit"
+	EndReasonDeadline  = "deadline"
 )
\ No newline at end of file
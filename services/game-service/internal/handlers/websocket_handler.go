@@ -8,6 +8,7 @@ import (
 
 	"game-service/config"
 	"game-service/internal/client"
+	"game-service/internal/constants"
 	ws "game-service/internal/websocket"
 
 	"github.com/gin-gonic/gin"
@@ -51,6 +52,13 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	role := c.DefaultQuery("role", constants.RoleParticipant)
+	if role != constants.RoleParticipant && role != constants.RoleSpectator {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+		return
+	}
+	isSpectator := role == constants.RoleSpectator
+
 	var isCreator bool
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -99,7 +107,12 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
-	client := ws.NewClient(h.hub, conn, userID, instanceID, isCreator)
+	// A spectator socket is read-only, even when the host opens it.
+	if isSpectator {
+		isCreator = false
+	}
+
+	client := ws.NewClient(h.hub, conn, userID, instanceID, isCreator, isSpectator)
 
 	h.hub.Register <- client
 
@@ -12,19 +12,67 @@ type GameSession struct {
 	CurrentQuestionIndex int
 	Score                int
 	Answers              string // JSON
+	QuestionOrder        string // JSON ShuffleOrder, empty when the quiz is not shuffled
+	CurrentStreak        int    // correct answers in a row, reset by a wrong or missed answer
+	BestStreak           int
+	TeamID               string // empty when the participant is in no team
 	StartedAt            time.Time
 	FinishedAt           sql.NullTime
 }
 
+// Team is a group of participants playing together in a sync quiz.
+type Team struct {
+	ID      string
+	Name    string
+	UserIDs []string
+}
+
+// TeamScore is a team's result aggregated from its members' sessions.
+type TeamScore struct {
+	TeamID       string
+	Name         string
+	Score        int
+	AverageScore float64
+	MemberCount  int
+	Rank         int
+}
+
+// ShuffleOrder is a participant's question order (by question ID) and, per
+// question, the canonical index of each option as displayed.
+type ShuffleOrder struct {
+	QuestionIDs []string         `json:"question_ids"`
+	Options     map[string][]int `json:"options,omitempty"`
+}
+
 type Answer struct {
 	QuestionID  string `json:"question_id"`
 	Answer      string `json:"answer"`
 	IsCorrect   bool   `json:"is_correct"`
 	Score       int    `json:"score"`
 	TimeSpentMs int64  `json:"time_spent_ms"`
+	NeedsReview bool   `json:"needs_review,omitempty"`
+	Feedback    string `json:"feedback,omitempty"`
+	GradedBy    string `json:"graded_by,omitempty"`
+	SubmittedAt string `json:"submitted_at,omitempty"`
+
+	// Scoring inputs, kept so the score can be recomputed with another
+	// strategy. Streak counts the correct answers in a row before this one.
+	Credit   float64 `json:"credit,omitempty"`
+	Strategy string  `json:"strategy,omitempty"`
+	Streak   int     `json:"streak,omitempty"`
+
+	// AISuggestion is filled in by ml-service after the session finishes.
+	AISuggestion *AISuggestion `json:"ai_suggestion,omitempty"`
+}
+
+type AISuggestion struct {
+	Similarity     float64 `json:"similarity"`
+	SuggestedScore int     `json:"suggested_score"`
+	Model          string  `json:"model"`
 }
 
 type QuizData struct {
+	Title      string     `json:"title"`
 	QuizType   string     `json:"quiz_type"`
 	CreatedBy  string     `json:"created_by"`
 	Questions  []Question `json:"questions"`
@@ -44,10 +92,17 @@ type Question struct {
 }
 
 type Settings struct {
-	RandomOrder        bool `json:"random_order"`
-	TimeLimitTotal     int  `json:"time_limit_total"`
-	ShowCorrectAnswers bool `json:"show_correct_answers"`
-	AllowReview        bool `json:"allow_review"`
+	RandomOrder           bool   `json:"random_order"`
+	TimeLimitTotal        int    `json:"time_limit_total"`
+	ShowCorrectAnswers    bool   `json:"show_correct_answers"`
+	AllowReview           bool   `json:"allow_review"`
+	ShuffleOptions        bool   `json:"shuffle_options"`
+	ScoringStrategy       string `json:"scoring_strategy,omitempty"`
+	ScoringFloorPercent   int    `json:"scoring_floor_percent,omitempty"`
+	ScoringPenaltyPercent int    `json:"scoring_penalty_percent,omitempty"`
+	StreakBonusPercent    int    `json:"streak_bonus_percent,omitempty"`
+	StreakBonusMaxPercent int    `json:"streak_bonus_max_percent,omitempty"`
+	LeaderboardRanking    string `json:"leaderboard_ranking,omitempty"`
 }
 
 type LeaderboardEntry struct {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"game-service/internal/models"
 )
@@ -18,8 +19,8 @@ func NewSessionRepository(db *sql.DB) *SessionRepository {
 
 func (r *SessionRepository) CreateSession(ctx context.Context, session *models.GameSession) error {
 	query := `
-		INSERT INTO game_sessions (instance_id, user_id, status, current_question_index, score, answers, started_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO game_sessions (instance_id, user_id, status, current_question_index, score, answers, started_at, question_order)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, '')::jsonb)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		session.InstanceID,
@@ -29,13 +30,15 @@ func (r *SessionRepository) CreateSession(ctx context.Context, session *models.G
 		session.Score,
 		session.Answers,
 		session.StartedAt,
+		session.QuestionOrder,
 	)
 	return err
 }
 
 func (r *SessionRepository) GetSession(ctx context.Context, instanceID, userID string) (*models.GameSession, error) {
 	query := `
-		SELECT instance_id, user_id, status, current_question_index, score, answers, started_at, finished_at
+		SELECT instance_id, user_id, status, current_question_index, score, answers, started_at, finished_at,
+			COALESCE(question_order::text, ''), current_streak, best_streak, COALESCE(team_id, '')
 		FROM game_sessions
 		WHERE instance_id = $1 AND user_id = $2
 	`
@@ -49,6 +52,10 @@ func (r *SessionRepository) GetSession(ctx context.Context, instanceID, userID s
 		&session.Answers,
 		&session.StartedAt,
 		&session.FinishedAt,
+		&session.QuestionOrder,
+		&session.CurrentStreak,
+		&session.BestStreak,
+		&session.TeamID,
 	)
 	if err != nil {
 		return nil, err
@@ -59,8 +66,9 @@ func (r *SessionRepository) GetSession(ctx context.Context, instanceID, userID s
 func (r *SessionRepository) UpdateSession(ctx context.Context, session *models.GameSession) error {
 	query := `
 		UPDATE game_sessions
-		SET status = $1, current_question_index = $2, score = $3, answers = $4, finished_at = $5
-		WHERE instance_id = $6 AND user_id = $7
+		SET status = $1, current_question_index = $2, score = $3, answers = $4, finished_at = $5,
+			current_streak = $6, best_streak = $7
+		WHERE instance_id = $8 AND user_id = $9
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		session.Status,
@@ -68,6 +76,8 @@ func (r *SessionRepository) UpdateSession(ctx context.Context, session *models.G
 		session.Score,
 		session.Answers,
 		session.FinishedAt,
+		session.CurrentStreak,
+		session.BestStreak,
 		session.InstanceID,
 		session.UserID,
 	)
@@ -76,7 +86,8 @@ func (r *SessionRepository) UpdateSession(ctx context.Context, session *models.G
 
 func (r *SessionRepository) GetSessionsByInstance(ctx context.Context, instanceID string) ([]*models.GameSession, error) {
 	query := `
-		SELECT instance_id, user_id, status, current_question_index, score, answers, started_at, finished_at
+		SELECT instance_id, user_id, status, current_question_index, score, answers, started_at, finished_at,
+			COALESCE(question_order::text, ''), current_streak, best_streak, COALESCE(team_id, '')
 		FROM game_sessions
 		WHERE instance_id = $1
 		ORDER BY score DESC, started_at ASC
@@ -85,6 +96,47 @@ func (r *SessionRepository) GetSessionsByInstance(ctx context.Context, instanceI
 	if err != nil {
 		return nil, err
 	}
+	return scanSessions(rows)
+}
+
+// FinishOpenSessions finishes every session of the instance that is still
+// joined or in progress and returns the sessions it finished.
+func (r *SessionRepository) FinishOpenSessions(ctx context.Context, instanceID string, finishedAt time.Time) ([]*models.GameSession, error) {
+	query := `
+		UPDATE game_sessions
+		SET status = 'finished', finished_at = $2
+		WHERE instance_id = $1 AND status IN ('joined', 'in_progress')
+		RETURNING instance_id, user_id, status, current_question_index, score, answers, started_at, finished_at,
+			COALESCE(question_order::text, ''), current_streak, best_streak, COALESCE(team_id, '')
+	`
+	rows, err := r.db.QueryContext(ctx, query, instanceID, finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	return scanSessions(rows)
+}
+
+// FinishSession finishes the session if it is still joined or in progress.
+// It reports false when the session was already closed, possibly by
+// quiz-service at the deadline.
+func (r *SessionRepository) FinishSession(ctx context.Context, instanceID, userID string, finishedAt time.Time) (bool, error) {
+	query := `
+		UPDATE game_sessions
+		SET status = 'finished', finished_at = $3
+		WHERE instance_id = $1 AND user_id = $2 AND status IN ('joined', 'in_progress')
+	`
+	result, err := r.db.ExecContext(ctx, query, instanceID, userID, finishedAt)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func scanSessions(rows *sql.Rows) ([]*models.GameSession, error) {
 	defer rows.Close()
 
 	var sessions []*models.GameSession
@@ -99,13 +151,17 @@ func (r *SessionRepository) GetSessionsByInstance(ctx context.Context, instanceI
 			&session.Answers,
 			&session.StartedAt,
 			&session.FinishedAt,
+			&session.QuestionOrder,
+			&session.CurrentStreak,
+			&session.BestStreak,
+			&session.TeamID,
 		)
 		if err != nil {
 			return nil, err
 		}
 		sessions = append(sessions, session)
 	}
-	return sessions, nil
+	return sessions, rows.Err()
 }
 
 func (r *SessionRepository) SessionExists(ctx context.Context, instanceID, userID string) (bool, error) {
@@ -140,4 +196,14 @@ func (r *SessionRepository) UpdateSessionStatus(ctx context.Context, instanceID,
 		return fmt.Errorf("session not found")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *SessionRepository) AdvanceQuestionIndex(ctx context.Context, instanceID string, questionIndex int) error {
+	query := `
+		UPDATE game_sessions
+		SET current_question_index = $1
+		WHERE instance_id = $2 AND current_question_index < $1 AND status IN ('joined', 'in_progress')
+	`
+	_, err := r.db.ExecContext(ctx, query, questionIndex, instanceID)
+	return err
+}
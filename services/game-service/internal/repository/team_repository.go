@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"game-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type TeamRepository struct {
+	db *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+// ReplaceTeams drops the instance's previous teams and assigns participants to
+// the new ones.
+func (r *TeamRepository) ReplaceTeams(ctx context.Context, instanceID string, teams []models.Team) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM game_teams WHERE instance_id = $1`, instanceID); err != nil {
+		return fmt.Errorf("failed to delete teams: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE game_sessions SET team_id = NULL WHERE instance_id = $1`, instanceID); err != nil {
+		return fmt.Errorf("failed to clear team members: %w", err)
+	}
+
+	for _, team := range teams {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO game_teams (instance_id, team_id, name, member_count)
+			VALUES ($1, $2, $3, $4)
+		`, instanceID, team.ID, team.Name, len(team.UserIDs))
+		if err != nil {
+			return fmt.Errorf("failed to insert team: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE game_sessions SET team_id = $1
+			WHERE instance_id = $2 AND user_id = ANY($3)
+		`, team.ID, instanceID, pq.Array(team.UserIDs))
+		if err != nil {
+			return fmt.Errorf("failed to assign team members: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetTeamScores sums the scores of each team's members. Kicked participants
+// do not count.
+func (r *TeamRepository) GetTeamScores(ctx context.Context, instanceID string) ([]models.TeamScore, error) {
+	query := `
+		SELECT t.team_id, t.name, COALESCE(SUM(s.score), 0), COUNT(s.user_id)
+		FROM game_teams t
+		LEFT JOIN game_sessions s
+			ON s.instance_id = t.instance_id AND s.team_id = t.team_id AND s.status <> 'kicked'
+		WHERE t.instance_id = $1
+		GROUP BY t.team_id, t.name
+	`
+	rows, err := r.db.QueryContext(ctx, query, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []models.TeamScore
+	for rows.Next() {
+		var score models.TeamScore
+		if err := rows.Scan(&score.TeamID, &score.Name, &score.Score, &score.MemberCount); err != nil {
+			return nil, err
+		}
+		if score.MemberCount > 0 {
+			score.AverageScore = math.Round(float64(score.Score)/float64(score.MemberCount)*100) / 100
+		}
+		scores = append(scores, score)
+	}
+	return scores, rows.Err()
+}
+
+// SaveTeamResults stores the final team scores and ranks for reporting.
+func (r *TeamRepository) SaveTeamResults(ctx context.Context, instanceID string, results []models.TeamScore) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, result := range results {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE game_teams
+			SET score = $1, average_score = $2, member_count = $3, rank = $4, finished_at = CURRENT_TIMESTAMP
+			WHERE instance_id = $5 AND team_id = $6
+		`, result.Score, result.AverageScore, result.MemberCount, result.Rank, instanceID, result.TeamID)
+		if err != nil {
+			return fmt.Errorf("failed to save result of team %s: %w", result.TeamID, err)
+		}
+	}
+
+	return tx.Commit()
+}
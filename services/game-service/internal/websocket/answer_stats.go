@@ -0,0 +1,75 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"math"
+
+	"game-service/internal/models"
+)
+
+func (h *Hub) sendAnswerHistogram(client *Client, quizData *models.QuizData, questionIndex int) {
+	histogram, err := h.answerHistogram(context.Background(), client.InstanceID, quizData, questionIndex)
+	if err != nil {
+		log.Printf("Failed to build answer histogram for instance %s: %v", client.InstanceID, err)
+		return
+	}
+	client.SendMessage(MessageTypeAnswerHistogram, histogram)
+}
+
+// broadcastQuestionSummary shows everyone the final answer statistics of a
+// sync question next to the leaderboard.
+func (h *Hub) broadcastQuestionSummary(ctx context.Context, instanceID string, questionIndex int) {
+	quizData, err := h.getQuizData(ctx, instanceID)
+	if err != nil {
+		log.Printf("Failed to get quiz data for question summary: %v", err)
+		return
+	}
+	if questionIndex >= len(quizData.Questions) {
+		return
+	}
+
+	summary, err := h.answerHistogram(ctx, instanceID, quizData, questionIndex)
+	if err != nil {
+		log.Printf("Failed to build question summary for instance %s: %v", instanceID, err)
+		return
+	}
+	if quizData.Settings.ShowCorrectAnswers {
+		summary.CorrectAnswer = displayedAnswer(&quizData.Questions[questionIndex], nil)
+	}
+	h.broadcastToInstance(instanceID, MessageTypeQuestionSummary, summary)
+}
+
+// summarizeAnswers computes the answer statistics of a question. Answers
+// waiting for manual review are left out of the percentage correct.
+func summarizeAnswers(question *models.Question, answers []models.Answer) AnswerHistogramPayload {
+	summary := AnswerHistogramPayload{
+		QuestionID: question.ID,
+		Answered:   len(answers),
+	}
+
+	texts := make([]string, len(answers))
+	var totalTimeMs int64
+	graded, correct := 0, 0
+	for i, a := range answers {
+		texts[i] = a.Answer
+		totalTimeMs += a.TimeSpentMs
+		if a.NeedsReview {
+			summary.PendingReview++
+			continue
+		}
+		graded++
+		if a.IsCorrect {
+			correct++
+		}
+	}
+
+	if len(answers) > 0 {
+		summary.AverageTimeMs = totalTimeMs / int64(len(answers))
+	}
+	if graded > 0 {
+		summary.CorrectPercent = math.Round(float64(correct)/float64(graded)*1000) / 10
+	}
+	summary.Buckets = histogramBuckets(question, texts)
+	return summary
+}
@@ -0,0 +1,31 @@
+package websocket
+
+import (
+	"reflect"
+	"testing"
+
+	"game-service/internal/models"
+)
+
+func TestSummarizeAnswers(t *testing.T) {
+	question := &models.Question{ID: "q1", Type: "multiple_choice", Options: []string{"A", "B"}}
+	summary := summarizeAnswers(question, []models.Answer{
+		{Answer: "0", IsCorrect: true, TimeSpentMs: 1000},
+		{Answer: "1", TimeSpentMs: 3000},
+		{Answer: "0", IsCorrect: true, TimeSpentMs: 2000},
+	})
+
+	if summary.QuestionID != "q1" || summary.Answered != 3 || summary.AverageTimeMs != 2000 || summary.CorrectPercent != 66.7 {
+		t.Fatalf("summary = %+v", summary)
+	}
+	if got := bucketCounts(summary.Buckets); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Fatalf("counts = %v", got)
+	}
+
+	open := summarizeAnswers(&models.Question{Type: "open"}, []models.Answer{
+		{Answer: "essay", NeedsReview: true, TimeSpentMs: 500},
+	})
+	if open.PendingReview != 1 || open.CorrectPercent != 0 || open.Buckets != nil {
+		t.Fatalf("open summary = %+v", open)
+	}
+}
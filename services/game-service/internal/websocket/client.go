@@ -22,16 +22,21 @@ type Client struct {
 	UserID     string
 	InstanceID string
 	IsCreator  bool
+
+	// IsSpectator marks a read-only socket, e.g. a projector view. It has no
+	// game session and is never counted as a participant.
+	IsSpectator bool
 }
 
-func NewClient(hub *Hub, conn *websocket.Conn, userID, instanceID string, isCreator bool) *Client {
+func NewClient(hub *Hub, conn *websocket.Conn, userID, instanceID string, isCreator, isSpectator bool) *Client {
 	return &Client{
-		Hub:        hub,
-		Conn:       conn,
-		Send:       make(chan []byte, 256),
-		UserID:     userID,
-		InstanceID: instanceID,
-		IsCreator:  isCreator,
+		Hub:         hub,
+		Conn:        conn,
+		Send:        make(chan []byte, 256),
+		UserID:      userID,
+		InstanceID:  instanceID,
+		IsCreator:   isCreator,
+		IsSpectator: isSpectator,
 	}
 }
 
@@ -128,6 +133,10 @@ func (c *Client) SendMessage(msgType MessageType, payload any) {
 		log.Printf("Failed to marshal message: %v", err)
 		return
 	}
+	c.sendRaw(data)
+}
+
+func (c *Client) sendRaw(data []byte) {
 	select {
 	case c.Send <- data:
 	default:
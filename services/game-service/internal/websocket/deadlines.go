@@ -0,0 +1,216 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"game-service/internal/constants"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// questionDeadlinesKey is a Redis sorted set of pending question deadlines
+// scored by their expiry in unix milliseconds. It outlives any single
+// replica, so deadlines survive restarts and are fired by whichever replica
+// claims them first.
+const questionDeadlinesKey = "game:question_deadlines"
+
+const (
+	deadlinePollInterval = 250 * time.Millisecond
+	deadlineBatchSize    = 100
+)
+
+// questionDeadline identifies a question timer, the total quiz timer when
+// Total is set, or the deadline of an async instance when Instance is set.
+// UserID is empty for sync quizzes, where one deadline covers the whole room.
+type questionDeadline struct {
+	InstanceID    string `json:"instance_id"`
+	UserID        string `json:"user_id,omitempty"`
+	QuestionIndex int    `json:"question_index"`
+	Total         bool   `json:"total,omitempty"`
+	Instance      bool   `json:"instance,omitempty"`
+}
+
+func (d questionDeadline) timerKey() string {
+	if d.Instance {
+		return fmt.Sprintf("%s:deadline", d.InstanceID)
+	}
+	if d.Total {
+		return fmt.Sprintf("%s:%s:total", d.InstanceID, d.UserID)
+	}
+	if d.UserID == "" {
+		return fmt.Sprintf("%s:%d", d.InstanceID, d.QuestionIndex)
+	}
+	return fmt.Sprintf("%s:%s:%d", d.InstanceID, d.UserID, d.QuestionIndex)
+}
+
+func (d questionDeadline) member() string {
+	data, _ := json.Marshal(d)
+	return string(data)
+}
+
+func (h *Hub) scheduleQuestionDeadline(d questionDeadline, duration time.Duration) {
+	if h.redisClient != nil {
+		deadline := time.Now().Add(duration).UnixMilli()
+		err := h.redisClient.GetClient().ZAdd(context.Background(), questionDeadlinesKey, redis.Z{
+			Score:  float64(deadline),
+			Member: d.member(),
+		}).Err()
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to persist question deadline %s, falling back to local timer: %v", d.timerKey(), err)
+	}
+
+	h.setQuestionTimer(d.timerKey(), duration, func() {
+		h.fireQuestionDeadline(d)
+	})
+}
+
+func (h *Hub) cancelQuestionDeadline(d questionDeadline) {
+	if h.redisClient != nil {
+		if err := h.redisClient.GetClient().ZRem(context.Background(), questionDeadlinesKey, d.member()).Err(); err != nil {
+			log.Printf("Failed to cancel question deadline %s: %v", d.timerKey(), err)
+		}
+	}
+	h.cancelQuestionTimer(d.timerKey())
+}
+
+// questionDeadlinePending reports whether the deadline is scheduled and has
+// not fired yet.
+func (h *Hub) questionDeadlinePending(ctx context.Context, d questionDeadline) bool {
+	if h.redisClient != nil {
+		err := h.redisClient.GetClient().ZScore(ctx, questionDeadlinesKey, d.member()).Err()
+		if err == nil {
+			return true
+		}
+		if err != redis.Nil {
+			log.Printf("Failed to look up question deadline %s: %v", d.timerKey(), err)
+		}
+	}
+
+	h.timerMu.Lock()
+	defer h.timerMu.Unlock()
+	_, ok := h.questionTimers[d.timerKey()]
+	return ok
+}
+
+func (h *Hub) setQuestionTimer(timerKey string, duration time.Duration, fn func()) {
+	h.timerMu.Lock()
+	defer h.timerMu.Unlock()
+
+	if timer, ok := h.questionTimers[timerKey]; ok {
+		timer.Stop()
+	}
+	h.questionTimers[timerKey] = time.AfterFunc(duration, fn)
+}
+
+// runDeadlineScheduler polls the deadline set. Deadlines that expired while
+// no replica was running are picked up on the first tick after startup.
+func (h *Hub) runDeadlineScheduler() {
+	if h.redisClient == nil {
+		return
+	}
+
+	log.Printf("Question deadline scheduler started: node=%s", h.nodeID)
+
+	ticker := time.NewTicker(deadlinePollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.fireDueDeadlines(context.Background(), time.Now())
+	}
+}
+
+// fireDueDeadlines claims and fires every deadline due at now. A deadline is
+// claimed by removing it from the set, so only one replica fires it.
+func (h *Hub) fireDueDeadlines(ctx context.Context, now time.Time) int {
+	rdb := h.redisClient.GetClient()
+
+	members, err := rdb.ZRangeByScore(ctx, questionDeadlinesKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: deadlineBatchSize,
+	}).Result()
+	if err != nil {
+		log.Printf("Failed to load due question deadlines: %v", err)
+		return 0
+	}
+
+	fired := 0
+	for _, member := range members {
+		removed, err := rdb.ZRem(ctx, questionDeadlinesKey, member).Result()
+		if err != nil {
+			log.Printf("Failed to claim question deadline %s: %v", member, err)
+			continue
+		}
+		if removed == 0 {
+			continue
+		}
+
+		var d questionDeadline
+		if err := json.Unmarshal([]byte(member), &d); err != nil {
+			log.Printf("Failed to parse question deadline %s: %v", member, err)
+			continue
+		}
+
+		fired++
+		go h.fireQuestionDeadline(d)
+	}
+	return fired
+}
+
+func (h *Hub) fireQuestionDeadline(d questionDeadline) {
+	if d.Instance {
+		h.handleInstanceDeadline(d.InstanceID)
+		return
+	}
+
+	if d.Total {
+		h.handleTotalTimeExpired(d)
+		return
+	}
+
+	if d.UserID == "" {
+		h.handleSyncQuestionTimeout(d.InstanceID, d.QuestionIndex)
+		return
+	}
+
+	h.relay(&relayEnvelope{
+		InstanceID:    d.InstanceID,
+		Kind:          relayKindQuestionTimeout,
+		UserID:        d.UserID,
+		QuestionIndex: d.QuestionIndex,
+	})
+}
+
+func (h *Hub) handleLocalQuestionTimeout(instanceID, userID string, questionIndex int) {
+	var clients []*Client
+	for _, c := range h.localClients(instanceID) {
+		if c.UserID == userID {
+			clients = append(clients, c)
+		}
+	}
+	if len(clients) == 0 {
+		return
+	}
+
+	session, err := h.sessionRepo.GetSession(context.Background(), instanceID, userID)
+	if err == nil && session.Status != constants.SessionStatusInProgress {
+		return
+	}
+
+	quizData, err := h.getQuizData(context.Background(), instanceID)
+	if err != nil {
+		log.Printf("Failed to get quiz data for question timeout: %v", err)
+		return
+	}
+
+	for _, c := range clients {
+		go h.handleQuestionTimeout(c, quizData, questionIndex)
+	}
+}
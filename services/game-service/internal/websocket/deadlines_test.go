@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestDueDeadlineFiresOnceAcrossHubs(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hubA := newTestHub(t, mr)
+	hubB := newTestHub(t, mr)
+	ctx := context.Background()
+
+	hubA.scheduleQuestionDeadline(questionDeadline{
+		InstanceID:    "inst-1",
+		UserID:        "user-a",
+		QuestionIndex: 0,
+	}, 10*time.Millisecond)
+
+	now := time.Now().Add(time.Second)
+	var wg sync.WaitGroup
+	fired := make([]int, 2)
+	for i, h := range []*Hub{hubA, hubB} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fired[i] = h.fireDueDeadlines(ctx, now)
+		}()
+	}
+	wg.Wait()
+
+	if total := fired[0] + fired[1]; total != 1 {
+		t.Fatalf("deadline fired %d times, want 1", total)
+	}
+}
+
+func TestPendingDeadlineRecoveredByNewHub(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	crashed := newTestHub(t, mr)
+	crashed.scheduleQuestionDeadline(questionDeadline{
+		InstanceID:    "inst-1",
+		UserID:        "user-a",
+		QuestionIndex: 3,
+	}, time.Minute)
+
+	restarted := newTestHub(t, mr)
+	if got := restarted.fireDueDeadlines(ctx, time.Now()); got != 0 {
+		t.Fatalf("fired %d deadlines before expiry, want 0", got)
+	}
+	if got := restarted.fireDueDeadlines(ctx, time.Now().Add(2*time.Minute)); got != 1 {
+		t.Fatalf("fired %d deadlines after expiry, want 1", got)
+	}
+}
+
+func TestCancelledDeadlineDoesNotFire(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	ctx := context.Background()
+
+	d := questionDeadline{InstanceID: "inst-1", QuestionIndex: 1}
+	hub.scheduleQuestionDeadline(d, 10*time.Millisecond)
+	hub.cancelQuestionDeadline(d)
+
+	if got := hub.fireDueDeadlines(ctx, time.Now().Add(time.Second)); got != 0 {
+		t.Fatalf("fired %d cancelled deadlines, want 0", got)
+	}
+}
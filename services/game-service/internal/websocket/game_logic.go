@@ -37,18 +37,16 @@ func (h *Hub) handleStartQuiz(client *Client) {
 			QuizType: quizData.QuizType,
 		})
 
-		h.mu.RLock()
-		clients := h.clients[client.InstanceID]
-		h.mu.RUnlock()
-
-		for c := range clients {
-			if !c.IsCreator {
-				if err := h.sessionRepo.UpdateSessionStatus(ctx, client.InstanceID, c.UserID, constants.SessionStatusInProgress); err != nil {
-					log.Printf("Failed to update session status for user %s: %v", c.UserID, err)
-				}
-				h.sendQuestion(c, quizData, 0)
+		for _, userID := range h.connectedUserIDs(ctx, client.InstanceID) {
+			if userID == quizData.CreatedBy {
+				continue
+			}
+			if err := h.sessionRepo.UpdateSessionStatus(ctx, client.InstanceID, userID, constants.SessionStatusInProgress); err != nil {
+				log.Printf("Failed to update session status for user %s: %v", userID, err)
 			}
 		}
+		h.startRoomClock(ctx, client.InstanceID, quizData)
+		h.broadcastQuestion(ctx, client.InstanceID, quizData, 0)
 		h.notifyCreatorProgress(ctx, client.InstanceID, 0)
 	} else {
 		client.SendMessage(MessageTypeQuizStarted, QuizStartedPayload{
@@ -57,6 +55,51 @@ func (h *Hub) handleStartQuiz(client *Client) {
 		h.sendQuestion(client, quizData, 0)
 	}
 }
+// HandleInstanceStarted consumes game.instance_started, which quiz-service
+// publishes when its scheduler opens an async instance, and starts the quiz
+// for participants already waiting on any replica.
+func (h *Hub) HandleInstanceStarted(ctx context.Context, body []byte) error {
+	var event struct {
+		InstanceID string `json:"instance_id"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal instance_started event: %w", err)
+	}
+
+	log.Printf("Instance %s started by schedule", event.InstanceID)
+	h.relay(&relayEnvelope{
+		InstanceID: event.InstanceID,
+		Kind:       relayKindStart,
+	})
+	return nil
+}
+
+// startLocalClients sends the first question to every socket on this replica
+// that joined before a scheduled start.
+func (h *Hub) startLocalClients(instanceID string) {
+	clients := h.localClients(instanceID)
+	if len(clients) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	quizData, err := h.loadQuizData(ctx, instanceID)
+	if err != nil {
+		log.Printf("Failed to get quiz data for scheduled start of %s: %v", instanceID, err)
+		return
+	}
+
+	for _, c := range clients {
+		if err := h.sessionRepo.UpdateSessionStatus(ctx, instanceID, c.UserID, constants.SessionStatusInProgress); err != nil {
+			log.Printf("Failed to update session status for user %s: %v", c.UserID, err)
+		}
+		c.SendMessage(MessageTypeQuizStarted, QuizStartedPayload{
+			QuizType: quizData.QuizType,
+		})
+		h.sendQuestion(c, quizData, 0)
+	}
+}
+
 func (h *Hub) handleResumeQuiz(client *Client, quizData *models.QuizData) {
 	ctx := context.Background()
 
@@ -67,15 +110,29 @@ func (h *Hub) handleResumeQuiz(client *Client, quizData *models.QuizData) {
 	}
 
 	if quizData.QuizType == constants.QuizTypeAsync {
+		if session.Status == constants.SessionStatusFinished {
+			h.finishQuiz(client)
+			return
+		}
 		h.sendQuestion(client, quizData, session.CurrentQuestionIndex)
 	} else {
-		var currentIndex int
-		if h.redisClient != nil {
-			indexKey := fmt.Sprintf("quiz:%s:current_index", client.InstanceID)
-			indexStr, err := h.redisClient.Get(ctx, indexKey)
-			if err == nil {
-				fmt.Sscanf(indexStr, "%d", &currentIndex)
+		currentIndex := h.currentQuestionIndex(ctx, client.InstanceID)
+
+		if state, paused := h.getPauseState(ctx, client.InstanceID); paused {
+			if !client.IsCreator && session.CurrentQuestionIndex <= state.QuestionIndex {
+				startTimeKey := fmt.Sprintf("quiz:%s:question:%d:start", client.InstanceID, state.QuestionIndex)
+				payload, _ := h.prepareQuestion(ctx, startTimeKey, quizData, state.QuestionIndex)
+				if payload.TimeLimitMs > 0 {
+					payload.TimeLimitMs = state.RemainingMs
+				}
+				setTotalRemaining(&payload, h.quizEndsAt(ctx, client.InstanceID, client.UserID, quizData))
+				client.SendMessage(MessageTypeQuestion, payload)
 			}
+			client.SendMessage(MessageTypeQuizPaused, QuizPausedPayload{
+				QuestionIndex: state.QuestionIndex,
+				RemainingMs:   state.RemainingMs,
+			})
+			return
 		}
 
 		startTimeKey := fmt.Sprintf("quiz:%s:question:%d:start", client.InstanceID, currentIndex)
@@ -109,6 +166,7 @@ func (h *Hub) handleResumeQuiz(client *Client, quizData *models.QuizData) {
 				})
 			} else {
 				h.notifyCreatorProgress(ctx, client.InstanceID, currentIndex)
+				h.sendAnswerHistogram(client, quizData, currentIndex)
 			}
 			return
 		}
@@ -143,22 +201,15 @@ func (h *Hub) sendLeaderboardToClient(client *Client, questionIndex int) {
 	client.SendMessage(MessageTypeLeaderboard, LeaderboardPayload{
 		Leaderboard: leaderboard,
 	})
+	if teams := h.getTeamLeaderboard(ctx, client.InstanceID); len(teams) > 0 {
+		client.SendMessage(MessageTypeTeamLeaderboard, teamLeaderboardPayload(teams))
+	}
 }
 
 func (h *Hub) notifyCreatorProgress(ctx context.Context, instanceID string, questionIndex int) {
-	h.mu.RLock()
-	clients := h.clients[instanceID]
-	h.mu.RUnlock()
-
-	var creator *Client
-	for c := range clients {
-		if c.IsCreator {
-			creator = c
-			break
-		}
-	}
-
-	if creator == nil {
+	quizData, err := h.getQuizData(ctx, instanceID)
+	if err != nil {
+		log.Printf("Failed to get quiz data in notifyCreatorProgress: %v", err)
 		return
 	}
 
@@ -171,7 +222,7 @@ func (h *Hub) notifyCreatorProgress(ctx context.Context, instanceID string, ques
 	answeredCount := 0
 
 	for _, session := range sessions {
-		if session.UserID == creator.UserID {
+		if session.UserID == quizData.CreatedBy {
 			continue
 		}
 
@@ -183,7 +234,7 @@ func (h *Hub) notifyCreatorProgress(ctx context.Context, instanceID string, ques
 		}
 	}
 
-	creator.SendMessage(MessageTypeWaitingForCreator, WaitingForCreatorPayload{
+	h.sendToCreator(instanceID, MessageTypeWaitingForCreator, WaitingForCreatorPayload{
 		QuestionIndex: questionIndex,
 		Reason:        fmt.Sprintf("Question in progress: %d/%d answered", answeredCount, participantCount),
 	})
@@ -197,8 +248,9 @@ func (h *Hub) sendQuestion(client *Client, quizData *models.QuizData, questionIn
 		return
 	}
 
-	question := quizData.Questions[questionIndex]
 	ctx := context.Background()
+	quizData, _ = h.participantQuizData(ctx, quizData, client.InstanceID, client.UserID)
+	question := quizData.Questions[questionIndex]
 
 	var startTimeKey string
 	if quizData.QuizType == constants.QuizTypeSync {
@@ -207,6 +259,50 @@ func (h *Hub) sendQuestion(client *Client, quizData *models.QuizData, questionIn
 		startTimeKey = fmt.Sprintf("quiz:%s:user:%s:question:%d:start", client.InstanceID, client.UserID, questionIndex)
 	}
 
+	payload, duration := h.prepareQuestion(ctx, startTimeKey, quizData, questionIndex)
+
+	endsAt := h.quizEndsAt(ctx, client.InstanceID, client.UserID, quizData)
+	setTotalRemaining(&payload, endsAt)
+	if quizData.QuizType == constants.QuizTypeAsync && !endsAt.IsZero() {
+		h.scheduleTotalDeadline(client.InstanceID, client.UserID, endsAt)
+	}
+
+	if quizData.QuizType == constants.QuizTypeSync && !client.IsCreator {
+		log.Printf("Sending question payload to participant %s", client.UserID)
+		client.SendMessage(MessageTypeQuestion, payload)
+	} else if quizData.QuizType == constants.QuizTypeAsync {
+		client.SendMessage(MessageTypeQuestion, payload)
+	} else {
+		log.Printf("Skipping question send for user %s (sync creator)", client.UserID)
+	}
+
+	if question.TimeLimitSec > 0 {
+		h.startQuestionTimer(client, quizData, questionIndex, duration)
+	}
+}
+
+// broadcastQuestion sends the current sync question to every participant of
+// the instance, whichever replica holds their socket.
+func (h *Hub) broadcastQuestion(ctx context.Context, instanceID string, quizData *models.QuizData, questionIndex int) {
+	log.Printf("Broadcasting question %d for instance %s", questionIndex, instanceID)
+
+	question := quizData.Questions[questionIndex]
+	startTimeKey := fmt.Sprintf("quiz:%s:question:%d:start", instanceID, questionIndex)
+
+	payload, duration := h.prepareQuestion(ctx, startTimeKey, quizData, questionIndex)
+	setTotalRemaining(&payload, h.quizEndsAt(ctx, instanceID, "", quizData))
+	h.broadcastToParticipants(instanceID, MessageTypeQuestion, payload)
+	h.broadcastToSpectators(instanceID, MessageTypeQuestion, payload)
+	h.broadcastAnswerHistogram(ctx, instanceID, quizData, questionIndex)
+
+	if question.TimeLimitSec > 0 {
+		h.startInstanceQuestionTimer(instanceID, questionIndex, duration)
+	}
+}
+
+func (h *Hub) prepareQuestion(ctx context.Context, startTimeKey string, quizData *models.QuizData, questionIndex int) (QuestionPayload, time.Duration) {
+	question := quizData.Questions[questionIndex]
+
 	if h.redisClient != nil {
 		h.redisClient.GetClient().SetNX(ctx, startTimeKey, time.Now().UnixMilli(), 1*time.Hour)
 	}
@@ -239,6 +335,7 @@ func (h *Hub) sendQuestion(client *Client, quizData *models.QuizData, questionIn
 			Text:         question.Text,
 			Type:         question.Type,
 			Options:      question.Options,
+			MatchOptions: matchOptions(&question),
 			OrderIndex:   question.OrderIndex,
 			MaxScore:     question.MaxScore,
 			TimeLimitSec: question.TimeLimitSec,
@@ -252,38 +349,37 @@ func (h *Hub) sendQuestion(client *Client, quizData *models.QuizData, questionIn
 		payload.TimeLimitMs = duration.Milliseconds()
 	}
 
-	if quizData.QuizType == constants.QuizTypeSync && !client.IsCreator {
-		log.Printf("Sending question payload to participant %s", client.UserID)
-		client.SendMessage(MessageTypeQuestion, payload)
-	} else if quizData.QuizType == constants.QuizTypeAsync {
-		client.SendMessage(MessageTypeQuestion, payload)
-	} else {
-		log.Printf("Skipping question send for user %s (sync creator)", client.UserID)
-	}
-
-	if question.TimeLimitSec > 0 {
-		h.startQuestionTimer(client, quizData, questionIndex, duration)
-	}
+	return payload, duration
 }
 
 func (h *Hub) startQuestionTimer(client *Client, quizData *models.QuizData, questionIndex int, duration time.Duration) {
-	var timerKey string
 	if quizData.QuizType == constants.QuizTypeSync {
-		timerKey = fmt.Sprintf("%s:%d", client.InstanceID, questionIndex)
-	} else {
-		timerKey = fmt.Sprintf("%s:%s:%d", client.InstanceID, client.UserID, questionIndex)
+		h.startInstanceQuestionTimer(client.InstanceID, questionIndex, duration)
+		return
 	}
 
-	h.timerMu.Lock()
-	if timer, ok := h.questionTimers[timerKey]; ok {
-		timer.Stop()
-	}
+	h.scheduleQuestionDeadline(questionDeadline{
+		InstanceID:    client.InstanceID,
+		UserID:        client.UserID,
+		QuestionIndex: questionIndex,
+	}, duration)
+}
 
-	timer := time.AfterFunc(duration, func() {
-		h.handleQuestionTimeout(client, quizData, questionIndex)
-	})
-	h.questionTimers[timerKey] = timer
-	h.timerMu.Unlock()
+func (h *Hub) startInstanceQuestionTimer(instanceID string, questionIndex int, duration time.Duration) {
+	h.scheduleQuestionDeadline(questionDeadline{
+		InstanceID:    instanceID,
+		QuestionIndex: questionIndex,
+	}, duration)
+}
+
+func (h *Hub) handleSyncQuestionTimeout(instanceID string, questionIndex int) {
+	log.Printf("Question timeout: instance=%s, question=%d", instanceID, questionIndex)
+
+	expired := TimeExpiredPayload{QuestionIndex: questionIndex}
+	h.broadcastToParticipants(instanceID, MessageTypeTimeExpired, expired)
+	h.broadcastToSpectators(instanceID, MessageTypeTimeExpired, expired)
+
+	h.showLeaderboardAndWait(instanceID, questionIndex)
 }
 
 func (h *Hub) handleQuestionTimeout(client *Client, quizData *models.QuizData, questionIndex int) {
@@ -291,11 +387,7 @@ func (h *Hub) handleQuestionTimeout(client *Client, quizData *models.QuizData, q
 		client.InstanceID, client.UserID, questionIndex)
 
 	if quizData.QuizType == constants.QuizTypeSync {
-		h.broadcastToParticipants(client.InstanceID, MessageTypeTimeExpired, TimeExpiredPayload{
-			QuestionIndex: questionIndex,
-		})
-
-		h.showLeaderboardAndWait(client.InstanceID, questionIndex)
+		h.handleSyncQuestionTimeout(client.InstanceID, questionIndex)
 	} else {
 		client.SendMessage(MessageTypeTimeExpired, TimeExpiredPayload{
 			QuestionIndex: questionIndex,
@@ -328,9 +420,11 @@ func (h *Hub) handleAnswer(client *Client, payload any) {
 		return
 	}
 
+	arranged, order := h.participantQuizData(ctx, quizData, client.InstanceID, client.UserID)
+
 	var question *models.Question
 	var questionIndex int
-	for i, q := range quizData.Questions {
+	for i, q := range arranged.Questions {
 		if q.ID == answerPayload.QuestionID {
 			question = &q
 			questionIndex = i
@@ -343,6 +437,15 @@ func (h *Hub) handleAnswer(client *Client, payload any) {
 		return
 	}
 
+	answer := canonicalAnswer(answerPayload.Answer, question, order)
+
+	if quizData.QuizType == constants.QuizTypeSync {
+		if _, paused := h.getPauseState(ctx, client.InstanceID); paused {
+			client.SendError("Quiz is paused")
+			return
+		}
+	}
+
 	var startTimeKey string
 	if quizData.QuizType == constants.QuizTypeSync {
 		startTimeKey = fmt.Sprintf("quiz:%s:question:%d:start", client.InstanceID, questionIndex)
@@ -373,13 +476,13 @@ func (h *Hub) handleAnswer(client *Client, payload any) {
 		}
 	}
 
-	isCorrect := h.validateAnswer(answerPayload.Answer, question.CorrectAnswer)
-
-	score := 0
-	if isCorrect {
-		timeLimitMs := int64(question.TimeLimitSec) * 1000
-		score = h.calculateScore(question.MaxScore, timeSpentMs, timeLimitMs)
+	// Free-text answers are left for the host to grade after the quiz.
+	needsReview := question.Type == constants.QuestionTypeOpen
+	credit := 0.0
+	if !needsReview {
+		credit = gradeAnswer(question, answer)
 	}
+	isCorrect := credit >= 1
 
 	session, err := h.sessionRepo.GetSession(ctx, client.InstanceID, client.UserID)
 	if err != nil {
@@ -388,55 +491,103 @@ func (h *Hub) handleAnswer(client *Client, payload any) {
 		return
 	}
 
+	if session.Status == constants.SessionStatusFinished || session.Status == constants.SessionStatusKicked {
+		client.SendError("You can no longer answer in this quiz")
+		return
+	}
+
 	var answers []models.Answer
 	if err := json.Unmarshal([]byte(session.Answers), &answers); err != nil {
 		log.Printf("Failed to parse answers: %v", err)
 		answers = []models.Answer{}
 	}
 
+	strategy := scoringStrategy(quizData.Settings)
+	streak := session.CurrentStreak
+	if questionIndex > session.CurrentQuestionIndex {
+		// A question was missed since the last answer.
+		streak = 0
+	}
+	score := 0
+	if !needsReview {
+		score = strategy.Score(ScoreInput{
+			QuestionType: question.Type,
+			MaxScore:     question.MaxScore,
+			Credit:       credit,
+			TimeSpentMs:  timeSpentMs,
+			TimeLimitMs:  int64(question.TimeLimitSec) * 1000,
+			Streak:       streak,
+		})
+	}
+
 	answers = append(answers, models.Answer{
 		QuestionID:  answerPayload.QuestionID,
-		Answer:      answerPayload.Answer,
+		Answer:      answer,
 		IsCorrect:   isCorrect,
 		Score:       score,
 		TimeSpentMs: timeSpentMs,
+		NeedsReview: needsReview,
+		SubmittedAt: time.Now().UTC().Format(time.RFC3339),
+		Credit:      credit,
+		Strategy:    strategy.Name(),
+		Streak:      streak,
 	})
 
 	answersJSON, _ := json.Marshal(answers)
 	session.Answers = string(answersJSON)
 	session.Score += score
 	session.CurrentQuestionIndex = questionIndex + 1
+	session.CurrentStreak = nextStreak(streak, isCorrect, needsReview)
+	session.BestStreak = max(session.BestStreak, session.CurrentStreak)
 
 	if err := h.sessionRepo.UpdateSession(ctx, session); err != nil {
 		log.Printf("Failed to update session: %v", err)
 		client.SendError("Failed to save answer")
 		return
 	}
+	h.updateLeaderboardEntry(ctx, session)
 
-	client.SendMessage(MessageTypeAnswerResult, AnswerResultPayload{
+	result := AnswerResultPayload{
 		IsCorrect:   isCorrect,
 		Score:       score,
 		TimeSpentMs: timeSpentMs,
 		TotalScore:  session.Score,
-	})
+
+		PendingReview: needsReview,
+
+		Streak:           session.CurrentStreak,
+		StreakMultiplier: streakMultiplier(strategy, streak),
+	}
+	// In a sync quiz others are still answering the same question, so the
+	// correct answer waits for the question summary.
+	if quizData.Settings.ShowCorrectAnswers && quizData.QuizType == constants.QuizTypeAsync {
+		result.CorrectAnswer = displayedAnswer(question, order)
+	}
+	client.SendMessage(MessageTypeAnswerResult, result)
 
 	if quizData.QuizType == constants.QuizTypeSync {
 		h.updateLeaderboard(ctx, client.InstanceID)
+		h.broadcastAnswerHistogram(ctx, client.InstanceID, quizData, questionIndex)
 	}
 
 	if quizData.QuizType == constants.QuizTypeSync {
 		allAnswered := h.checkAllParticipantsAnswered(ctx, client.InstanceID, questionIndex)
 		if allAnswered {
-			timerKey := fmt.Sprintf("%s:%d", client.InstanceID, questionIndex)
-			h.cancelQuestionTimer(timerKey)
+			h.cancelQuestionDeadline(questionDeadline{
+				InstanceID:    client.InstanceID,
+				QuestionIndex: questionIndex,
+			})
 
 			h.showLeaderboardAndWait(client.InstanceID, questionIndex)
 		} else {
 			h.notifyCreatorProgress(ctx, client.InstanceID, questionIndex)
 		}
 	} else {
-		timerKey := fmt.Sprintf("%s:%s:%d", client.InstanceID, client.UserID, questionIndex)
-		h.cancelQuestionTimer(timerKey)
+		h.cancelQuestionDeadline(questionDeadline{
+			InstanceID:    client.InstanceID,
+			UserID:        client.UserID,
+			QuestionIndex: questionIndex,
+		})
 
 		time.Sleep(200 * time.Millisecond)
 		h.sendQuestion(client, quizData, questionIndex+1)
@@ -467,30 +618,26 @@ func (h *Hub) handleContinue(client *Client) {
 		}
 	}
 
-	h.mu.RLock()
-	clients := h.clients[client.InstanceID]
-	h.mu.RUnlock()
-
 	if nextQuestionIndex >= len(quizData.Questions) {
 		log.Printf("Quiz %s finished, updating status", client.InstanceID)
-		if err := h.updateInstanceStatus(ctx, client.InstanceID, constants.InstanceStatusFinished); err != nil {
+		if err := h.finishInstance(ctx, client.InstanceID); err != nil {
 			log.Printf("Failed to update instance status: %v", err)
 		}
-
-		for c := range clients {
-			h.finishQuiz(c)
-		}
 		return
 	}
 
-	if quizData.QuizType == constants.QuizTypeSync && h.redisClient != nil {
-		indexKey := fmt.Sprintf("quiz:%s:current_index", client.InstanceID)
-		h.redisClient.Set(ctx, indexKey, nextQuestionIndex, 24*time.Hour)
-	}
-
-	for c := range clients {
-		if !c.IsCreator {
-			h.sendQuestion(c, quizData, nextQuestionIndex)
+	if quizData.QuizType == constants.QuizTypeSync {
+		h.unpause(ctx, client.InstanceID, quizData)
+		if h.redisClient != nil {
+			indexKey := fmt.Sprintf("quiz:%s:current_index", client.InstanceID)
+			h.redisClient.Set(ctx, indexKey, nextQuestionIndex, 24*time.Hour)
+		}
+		h.broadcastQuestion(ctx, client.InstanceID, quizData, nextQuestionIndex)
+	} else {
+		for _, c := range h.localClients(client.InstanceID) {
+			if !c.IsCreator {
+				h.sendQuestion(c, quizData, nextQuestionIndex)
+			}
 		}
 	}
 	h.notifyCreatorProgress(ctx, client.InstanceID, nextQuestionIndex)
@@ -504,6 +651,8 @@ func (h *Hub) showLeaderboardAndWait(instanceID string, questionIndex int) {
 	h.broadcastToInstance(instanceID, MessageTypeLeaderboard, LeaderboardPayload{
 		Leaderboard: leaderboard,
 	})
+	h.broadcastTeamLeaderboard(ctx, instanceID)
+	h.broadcastQuestionSummary(ctx, instanceID, questionIndex)
 
 	h.broadcastToParticipants(instanceID, MessageTypeWaitingForCreator, WaitingForCreatorPayload{
 		QuestionIndex: questionIndex,
@@ -551,37 +700,7 @@ func (h *Hub) updateLeaderboard(ctx context.Context, instanceID string) {
 	h.broadcastToInstance(instanceID, MessageTypeLeaderboard, LeaderboardPayload{
 		Leaderboard: leaderboard,
 	})
-}
-
-func (h *Hub) getLeaderboard(ctx context.Context, instanceID string) []LeaderboardEntry {
-	sessions, err := h.sessionRepo.GetSessionsByInstance(ctx, instanceID)
-	if err != nil {
-		log.Printf("Failed to get sessions for leaderboard: %v", err)
-		return []LeaderboardEntry{}
-	}
-
-	quizData, err := h.getQuizData(ctx, instanceID)
-	if err != nil {
-		log.Printf("Failed to get quiz data: %v", err)
-		return []LeaderboardEntry{}
-	}
-
-	var leaderboard []LeaderboardEntry
-	rank := 1
-	for _, session := range sessions {
-		if session.UserID == quizData.CreatedBy {
-			continue
-		}
-
-		leaderboard = append(leaderboard, LeaderboardEntry{
-			Rank:   rank,
-			UserID: session.UserID,
-			Score:  session.Score,
-		})
-		rank++
-	}
-
-	return leaderboard
+	h.broadcastTeamLeaderboard(ctx, instanceID)
 }
 
 func (h *Hub) finishQuiz(client *Client) {
@@ -594,14 +713,23 @@ func (h *Hub) finishQuiz(client *Client) {
 		return
 	}
 
-	session.Status = constants.SessionStatusFinished
-	session.FinishedAt.Valid = true
-	session.FinishedAt.Time = time.Now()
-
-	if err := h.sessionRepo.UpdateSession(ctx, session); err != nil {
-		log.Printf("Failed to update session: %v", err)
+	if session.Status != constants.SessionStatusFinished {
+		finished, err := h.sessionRepo.FinishSession(ctx, client.InstanceID, client.UserID, time.Now())
+		if err != nil {
+			log.Printf("Failed to update session: %v", err)
+		} else if finished {
+			if quizData, err := h.getQuizData(ctx, client.InstanceID); err == nil {
+				h.requestGrading(ctx, quizData, session)
+			}
+		}
 	}
 
+	h.cancelQuestionDeadline(questionDeadline{
+		InstanceID: client.InstanceID,
+		UserID:     client.UserID,
+		Total:      true,
+	})
+
 	leaderboard := h.getLeaderboard(ctx, client.InstanceID)
 	rank := 0
 	for _, entry := range leaderboard {
@@ -0,0 +1,192 @@
+package websocket
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+)
+
+type numericAnswer struct {
+	Value     float64 `json:"value"`
+	Tolerance float64 `json:"tolerance"`
+}
+
+type grader func(answer, correctAnswerJSON string) float64
+
+var graders = map[string]grader{
+	constants.QuestionTypeMultipleChoice: gradeExact,
+	constants.QuestionTypeMultiSelect:    gradeMultiSelect,
+	constants.QuestionTypeOrdering:       gradeOrdering,
+	constants.QuestionTypeNumeric:        gradeNumeric,
+	constants.QuestionTypeTrueFalse:      gradeTrueFalse,
+	constants.QuestionTypeMatching:       gradeMatching,
+}
+
+// gradeAnswer returns the share of the question's score the answer earns,
+// from 0 to 1. Answers in canonical form are expected: option indices refer
+// to the question's own option order, not a shuffled one.
+func gradeAnswer(question *models.Question, answer string) float64 {
+	g, ok := graders[question.Type]
+	if !ok {
+		g = gradeExact
+	}
+	return g(answer, question.CorrectAnswer)
+}
+
+func gradeExact(answer, correctAnswerJSON string) float64 {
+	if normalizeText(answer) == normalizeText(decodeCorrectAnswer(correctAnswerJSON)) {
+		return 1
+	}
+	return 0
+}
+
+// gradeMultiSelect gives credit for each correct option picked and takes it
+// back for each wrong one, never going below zero.
+func gradeMultiSelect(answer, correctAnswerJSON string) float64 {
+	var correct []int
+	if err := json.Unmarshal([]byte(correctAnswerJSON), &correct); err != nil || len(correct) == 0 {
+		return 0
+	}
+	picked, ok := parseIndexList(answer)
+	if !ok {
+		return 0
+	}
+
+	isCorrect := make(map[int]bool, len(correct))
+	for _, idx := range correct {
+		isCorrect[idx] = true
+	}
+
+	hits, misses := 0, 0
+	seen := make(map[int]bool, len(picked))
+	for _, idx := range picked {
+		if seen[idx] {
+			continue
+		}
+		seen[idx] = true
+		if isCorrect[idx] {
+			hits++
+		} else {
+			misses++
+		}
+	}
+
+	return max(float64(hits-misses)/float64(len(correct)), 0)
+}
+
+// gradeOrdering gives credit for every item placed in its correct position.
+func gradeOrdering(answer, correctAnswerJSON string) float64 {
+	var correct []int
+	if err := json.Unmarshal([]byte(correctAnswerJSON), &correct); err != nil || len(correct) == 0 {
+		return 0
+	}
+	order, ok := parseIndexList(answer)
+	if !ok {
+		return 0
+	}
+
+	matched := 0
+	for i := range min(len(order), len(correct)) {
+		if order[i] == correct[i] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(correct))
+}
+
+func gradeNumeric(answer, correctAnswerJSON string) float64 {
+	value, err := parseNumber(answer)
+	if err != nil {
+		return 0
+	}
+
+	var correct numericAnswer
+	if err := json.Unmarshal([]byte(correctAnswerJSON), &correct); err != nil {
+		// Older questions store the number as a plain JSON string.
+		if correct.Value, err = parseNumber(decodeCorrectAnswer(correctAnswerJSON)); err != nil {
+			return 0
+		}
+	}
+
+	if math.Abs(value-correct.Value) <= correct.Tolerance+1e-9 {
+		return 1
+	}
+	return 0
+}
+
+func gradeTrueFalse(answer, correctAnswerJSON string) float64 {
+	value, err := strconv.ParseBool(normalizeText(answer))
+	if err != nil {
+		return 0
+	}
+	correct, err := strconv.ParseBool(normalizeText(decodeCorrectAnswer(correctAnswerJSON)))
+	if err != nil || value != correct {
+		return 0
+	}
+	return 1
+}
+
+// gradeMatching gives credit for every left-hand item matched with its
+// right-hand item. The answer lists the chosen right-hand item per option.
+func gradeMatching(answer, correctAnswerJSON string) float64 {
+	var correct []string
+	if err := json.Unmarshal([]byte(correctAnswerJSON), &correct); err != nil || len(correct) == 0 {
+		return 0
+	}
+	var chosen []string
+	if err := json.Unmarshal([]byte(answer), &chosen); err != nil {
+		return 0
+	}
+
+	matched := 0
+	for i := range min(len(chosen), len(correct)) {
+		if normalizeText(chosen[i]) == normalizeText(correct[i]) {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(correct))
+}
+
+// parseIndexList accepts a JSON array of indices or a comma separated list.
+func parseIndexList(answer string) ([]int, bool) {
+	var indices []int
+	if err := json.Unmarshal([]byte(answer), &indices); err == nil {
+		return indices, true
+	}
+
+	for _, part := range strings.Split(answer, ",") {
+		idx, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, false
+		}
+		indices = append(indices, idx)
+	}
+	return indices, true
+}
+
+func parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+}
+
+func normalizeText(s string) string {
+	return strings.TrimSpace(strings.ToLower(s))
+}
+
+// matchOptions returns the right-hand items of a matching question in a fixed
+// order that does not reveal the pairs.
+func matchOptions(question *models.Question) []string {
+	if question.Type != constants.QuestionTypeMatching {
+		return nil
+	}
+	var items []string
+	if err := json.Unmarshal([]byte(question.CorrectAnswer), &items); err != nil {
+		return nil
+	}
+	slices.Sort(items)
+	return items
+}
@@ -0,0 +1,60 @@
+package websocket
+
+import (
+	"testing"
+
+	"game-service/internal/models"
+)
+
+func TestGradeAnswerByType(t *testing.T) {
+	tests := []struct {
+		name     string
+		question models.Question
+		answer   string
+		want     float64
+	}{
+		{"choice by text", models.Question{Type: "multiple_choice", CorrectAnswer: `"Paris"`}, " paris ", 1},
+		{"choice wrong", models.Question{Type: "multiple_choice", CorrectAnswer: `"2"`}, "1", 0},
+		{"multi select all", models.Question{Type: "multi_select", CorrectAnswer: `[0,2]`}, "[2,0]", 1},
+		{"multi select partial", models.Question{Type: "multi_select", CorrectAnswer: `[0,2]`}, "0", 0.5},
+		{"multi select wrong pick", models.Question{Type: "multi_select", CorrectAnswer: `[0,2]`}, "0,1", 0},
+		{"ordering", models.Question{Type: "ordering", CorrectAnswer: `[2,0,1,3]`}, "[2,0,3,1]", 0.5},
+		{"numeric within tolerance", models.Question{Type: "numeric", CorrectAnswer: `{"value":9.8,"tolerance":0.1}`}, "9,75", 1},
+		{"numeric outside tolerance", models.Question{Type: "numeric", CorrectAnswer: `{"value":9.8,"tolerance":0.1}`}, "9.6", 0},
+		{"numeric legacy string", models.Question{Type: "numeric", CorrectAnswer: `"42"`}, "42", 1},
+		{"true false", models.Question{Type: "true_false", CorrectAnswer: `false`}, "False", 1},
+		{"true false wrong", models.Question{Type: "true_false", CorrectAnswer: `true`}, "false", 0},
+		{"matching", models.Question{Type: "matching", CorrectAnswer: `["H2O","NaCl","CO2"]`}, `["h2o","CO2","NaCl"]`, 1.0 / 3},
+		{"malformed answer", models.Question{Type: "ordering", CorrectAnswer: `[1,0]`}, "first", 0},
+	}
+
+	for _, tt := range tests {
+		if got := gradeAnswer(&tt.question, tt.answer); got != tt.want {
+			t.Errorf("%s: gradeAnswer(%q) = %v, want %v", tt.name, tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalAnswerForIndexListsAndMatching(t *testing.T) {
+	multi := &models.Question{ID: "m", Type: "multi_select", Options: []string{"a", "b", "c"}, CorrectAnswer: `[0,2]`}
+	matching := &models.Question{ID: "p", Type: "matching", Options: []string{"x", "y", "z"}, CorrectAnswer: `["1","2","3"]`}
+	order := &models.ShuffleOrder{Options: map[string][]int{
+		"m": {2, 0, 1},
+		"p": {1, 2, 0},
+	}}
+
+	if got := canonicalAnswer("[0,1]", multi, order); got != "[2,0]" {
+		t.Fatalf("multi select canonical = %s, want [2,0]", got)
+	}
+	if got := displayedAnswer(multi, order); got != "[0,1]" {
+		t.Fatalf("multi select displayed = %s, want [0,1]", got)
+	}
+
+	displayed := displayedAnswer(matching, order)
+	if displayed != `["2","3","1"]` {
+		t.Fatalf("matching displayed = %s", displayed)
+	}
+	if got := canonicalAnswer(displayed, matching, order); gradeAnswer(matching, got) != 1 {
+		t.Fatalf("matching canonical = %s does not grade as correct", got)
+	}
+}
@@ -0,0 +1,92 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+)
+
+const gradingRequestsQueue = "ml.grading_requests"
+
+type gradingAnswerData struct {
+	QuestionID    string `json:"question_id"`
+	QuestionText  string `json:"question_text"`
+	Answer        string `json:"answer"`
+	CorrectAnswer string `json:"correct_answer"`
+	MaxScore      int    `json:"max_score"`
+}
+
+type gradingRequestEvent struct {
+	InstanceID string              `json:"instance_id"`
+	UserID     string              `json:"user_id"`
+	Answers    []gradingAnswerData `json:"answers"`
+}
+
+// requestGrading asks ml-service for grading suggestions on the session's
+// open answers that still wait for the host's review.
+func (h *Hub) requestGrading(ctx context.Context, quizData *models.QuizData, session *models.GameSession) {
+	if h.mqPublisher == nil || !hasOpenQuestions(quizData) {
+		return
+	}
+
+	var answers []models.Answer
+	if err := json.Unmarshal([]byte(session.Answers), &answers); err != nil {
+		log.Printf("Failed to parse answers for grading: %v", err)
+		return
+	}
+
+	questions := make(map[string]*models.Question, len(quizData.Questions))
+	for i := range quizData.Questions {
+		questions[quizData.Questions[i].ID] = &quizData.Questions[i]
+	}
+
+	event := gradingRequestEvent{
+		InstanceID: session.InstanceID,
+		UserID:     session.UserID,
+	}
+	for _, a := range answers {
+		q, ok := questions[a.QuestionID]
+		if !ok || q.Type != constants.QuestionTypeOpen || !a.NeedsReview {
+			continue
+		}
+		event.Answers = append(event.Answers, gradingAnswerData{
+			QuestionID:    q.ID,
+			QuestionText:  q.Text,
+			Answer:        a.Answer,
+			CorrectAnswer: decodeCorrectAnswer(q.CorrectAnswer),
+			MaxScore:      q.MaxScore,
+		})
+	}
+	if len(event.Answers) == 0 {
+		return
+	}
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to marshal grading_request event: %v", err)
+		return
+	}
+
+	if err := h.mqPublisher.Publish(ctx, gradingRequestsQueue, eventJSON); err != nil {
+		log.Printf("Failed to publish grading_request event: %v", err)
+	}
+}
+
+// finishOpenSessions finishes the sessions still open when the quiz ends and
+// requests grading for exactly those. Sessions that finished on their own
+// were already sent.
+func (h *Hub) finishOpenSessions(ctx context.Context, quizData *models.QuizData, instanceID string) {
+	sessions, err := h.sessionRepo.FinishOpenSessions(ctx, instanceID, time.Now())
+	if err != nil {
+		log.Printf("Failed to finish open sessions: %v", err)
+		return
+	}
+
+	for _, session := range sessions {
+		h.requestGrading(ctx, quizData, session)
+	}
+}
@@ -0,0 +1,64 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"game-service/internal/models"
+)
+
+type recordingPublisher struct {
+	mu    sync.Mutex
+	queue string
+	body  []byte
+	count int
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, queueName string, body []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = queueName
+	p.body = body
+	p.count++
+	return nil
+}
+
+func (p *recordingPublisher) published() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.count
+}
+
+func TestRequestGradingSendsOnlyOpenAnswersAwaitingReview(t *testing.T) {
+	publisher := &recordingPublisher{}
+	hub := &Hub{mqPublisher: publisher}
+
+	quizData := &models.QuizData{
+		Questions: []models.Question{
+			{ID: "q1", Type: "open", Text: "Capital of France?", CorrectAnswer: `"Paris"`, MaxScore: 5},
+			{ID: "q2", Type: "multiple_choice", Options: []string{"a", "b"}, CorrectAnswer: `"1"`, MaxScore: 1},
+			{ID: "q3", Type: "open", CorrectAnswer: `"Rome"`, MaxScore: 5},
+		},
+	}
+	answers, _ := json.Marshal([]models.Answer{
+		{QuestionID: "q1", Answer: "paris", NeedsReview: true},
+		{QuestionID: "q2", Answer: "1", IsCorrect: true, Score: 1},
+		{QuestionID: "q3", Answer: "rome", Score: 5},
+	})
+	session := &models.GameSession{InstanceID: "inst-1", UserID: "user-a", Answers: string(answers)}
+
+	hub.requestGrading(context.Background(), quizData, session)
+
+	if publisher.queue != gradingRequestsQueue {
+		t.Fatalf("published to %q, want %q", publisher.queue, gradingRequestsQueue)
+	}
+	var event gradingRequestEvent
+	if err := json.Unmarshal(publisher.body, &event); err != nil {
+		t.Fatalf("invalid event: %v", err)
+	}
+	if len(event.Answers) != 1 || event.Answers[0].QuestionID != "q1" || event.Answers[0].CorrectAnswer != "Paris" {
+		t.Fatalf("event answers = %+v, want only q1 with decoded correct answer", event.Answers)
+	}
+}
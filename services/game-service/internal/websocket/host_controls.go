@@ -0,0 +1,423 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+)
+
+// pauseState is stored under quiz:<instance>:paused while a sync quiz is
+// paused. RemainingMs freezes the question timer until the host resumes;
+// PausedAt lets the total quiz clock skip the pause as well.
+type pauseState struct {
+	QuestionIndex int   `json:"question_index"`
+	RemainingMs   int64 `json:"remaining_ms"`
+	PausedAt      int64 `json:"paused_at"`
+}
+
+func pauseKey(instanceID string) string {
+	return fmt.Sprintf("quiz:%s:paused", instanceID)
+}
+
+func (h *Hub) getPauseState(ctx context.Context, instanceID string) (*pauseState, bool) {
+	if h.redisClient == nil {
+		return nil, false
+	}
+
+	data, err := h.redisClient.Get(ctx, pauseKey(instanceID))
+	if err != nil {
+		return nil, false
+	}
+
+	var state pauseState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		log.Printf("Failed to parse pause state for instance %s: %v", instanceID, err)
+		return nil, false
+	}
+	return &state, true
+}
+
+func (h *Hub) clearPauseState(ctx context.Context, instanceID string) {
+	if h.redisClient == nil {
+		return
+	}
+	if err := h.redisClient.Delete(ctx, pauseKey(instanceID)); err != nil {
+		log.Printf("Failed to clear pause state for instance %s: %v", instanceID, err)
+	}
+}
+
+func (h *Hub) currentQuestionIndex(ctx context.Context, instanceID string) int {
+	var currentIndex int
+	if h.redisClient != nil {
+		indexKey := fmt.Sprintf("quiz:%s:current_index", instanceID)
+		indexStr, err := h.redisClient.Get(ctx, indexKey)
+		if err == nil {
+			fmt.Sscanf(indexStr, "%d", &currentIndex)
+		}
+	}
+	return currentIndex
+}
+
+// questionStartTime returns the unix ms at which the sync question was sent,
+// or 0 if it has not been sent yet.
+func (h *Hub) questionStartTime(ctx context.Context, instanceID string, questionIndex int) int64 {
+	if h.redisClient == nil {
+		return 0
+	}
+
+	startTimeKey := fmt.Sprintf("quiz:%s:question:%d:start", instanceID, questionIndex)
+	startTimeStr, err := h.redisClient.Get(ctx, startTimeKey)
+	if err != nil {
+		return 0
+	}
+
+	var startTime int64
+	fmt.Sscanf(startTimeStr, "%d", &startTime)
+	return startTime
+}
+
+func (h *Hub) handlePauseQuiz(client *Client) {
+	ctx := context.Background()
+
+	quizData, err := h.getQuizData(ctx, client.InstanceID)
+	if err != nil {
+		log.Printf("Failed to get quiz data: %v", err)
+		client.SendError("Failed to pause quiz")
+		return
+	}
+
+	if quizData.QuizType != constants.QuizTypeSync {
+		client.SendError("Only sync quizzes can be paused")
+		return
+	}
+
+	if h.redisClient == nil {
+		client.SendError("Failed to pause quiz")
+		return
+	}
+
+	if _, paused := h.getPauseState(ctx, client.InstanceID); paused {
+		client.SendError("Quiz is already paused")
+		return
+	}
+
+	questionIndex := h.currentQuestionIndex(ctx, client.InstanceID)
+	startTime := h.questionStartTime(ctx, client.InstanceID, questionIndex)
+	if startTime == 0 || questionIndex >= len(quizData.Questions) {
+		client.SendError("No question in progress")
+		return
+	}
+
+	var remainingMs int64
+	question := quizData.Questions[questionIndex]
+	questionTimer := questionDeadline{
+		InstanceID:    client.InstanceID,
+		QuestionIndex: questionIndex,
+	}
+	if question.TimeLimitSec > 0 {
+		elapsed := time.Now().UnixMilli() - startTime
+		remainingMs = max(int64(question.TimeLimitSec)*1000-elapsed, 0)
+
+		// Once the question has timed out there is nothing left to freeze;
+		// resuming would fire the timeout a second time.
+		if remainingMs == 0 || !h.questionDeadlinePending(ctx, questionTimer) {
+			client.SendError("Question time is already up")
+			return
+		}
+	}
+
+	h.cancelQuestionDeadline(questionTimer)
+	h.cancelQuestionDeadline(questionDeadline{
+		InstanceID: client.InstanceID,
+		Total:      true,
+	})
+
+	state := pauseState{
+		QuestionIndex: questionIndex,
+		RemainingMs:   remainingMs,
+		PausedAt:      time.Now().UnixMilli(),
+	}
+	data, _ := json.Marshal(state)
+	if err := h.redisClient.Set(ctx, pauseKey(client.InstanceID), data, 24*time.Hour); err != nil {
+		log.Printf("Failed to store pause state: %v", err)
+		client.SendError("Failed to pause quiz")
+		h.resumeQuestionTimer(ctx, client.InstanceID, quizData, &state)
+		return
+	}
+
+	log.Printf("Quiz %s paused at question %d with %dms remaining", client.InstanceID, questionIndex, remainingMs)
+
+	h.broadcastToInstance(client.InstanceID, MessageTypeQuizPaused, QuizPausedPayload{
+		QuestionIndex: questionIndex,
+		RemainingMs:   remainingMs,
+	})
+}
+
+func (h *Hub) handleResumePausedQuiz(client *Client) {
+	ctx := context.Background()
+
+	state, paused := h.getPauseState(ctx, client.InstanceID)
+	if !paused {
+		client.SendError("Quiz is not paused")
+		return
+	}
+
+	quizData, err := h.getQuizData(ctx, client.InstanceID)
+	if err != nil {
+		log.Printf("Failed to get quiz data: %v", err)
+		client.SendError("Failed to resume quiz")
+		return
+	}
+
+	h.resumeQuestionTimer(ctx, client.InstanceID, quizData, state)
+	h.clearPauseState(ctx, client.InstanceID)
+
+	log.Printf("Quiz %s resumed at question %d", client.InstanceID, state.QuestionIndex)
+
+	h.broadcastToInstance(client.InstanceID, MessageTypeQuizResumed, QuizResumedPayload{
+		QuestionIndex: state.QuestionIndex,
+		RemainingMs:   state.RemainingMs,
+		ServerTime:    time.Now().UnixMilli(),
+	})
+	h.notifyCreatorProgress(ctx, client.InstanceID, state.QuestionIndex)
+}
+
+// resumeQuestionTimer shifts the question start time forward by the length of
+// the pause, so answer timing and reconnects see only the time actually
+// spent on the question, and reschedules the deadlines.
+func (h *Hub) resumeQuestionTimer(ctx context.Context, instanceID string, quizData *models.QuizData, state *pauseState) {
+	h.resumeRoomClock(ctx, instanceID, quizData)
+
+	if state.QuestionIndex >= len(quizData.Questions) {
+		return
+	}
+
+	question := quizData.Questions[state.QuestionIndex]
+	if question.TimeLimitSec <= 0 || state.RemainingMs <= 0 {
+		return
+	}
+
+	limitMs := int64(question.TimeLimitSec) * 1000
+	startTime := time.Now().UnixMilli() - (limitMs - state.RemainingMs)
+	startTimeKey := fmt.Sprintf("quiz:%s:question:%d:start", instanceID, state.QuestionIndex)
+	if err := h.redisClient.Set(ctx, startTimeKey, startTime, 1*time.Hour); err != nil {
+		log.Printf("Failed to shift question start time: %v", err)
+	}
+
+	h.startInstanceQuestionTimer(instanceID, state.QuestionIndex, time.Duration(state.RemainingMs)*time.Millisecond)
+}
+
+// resumeRoomClock moves the start of the total quiz clock forward by the
+// length of the pause and reschedules the total deadline. It must run while
+// the pause state is still stored.
+func (h *Hub) resumeRoomClock(ctx context.Context, instanceID string, quizData *models.QuizData) {
+	endsAt := h.quizEndsAt(ctx, instanceID, "", quizData)
+	if endsAt.IsZero() {
+		return
+	}
+
+	startedAt := endsAt.Add(-totalTimeLimit(quizData))
+	if err := h.redisClient.Set(ctx, quizStartedAtKey(instanceID), startedAt.UnixMilli(), 24*time.Hour); err != nil {
+		log.Printf("Failed to shift quiz start time: %v", err)
+	}
+	h.scheduleTotalDeadline(instanceID, "", endsAt)
+}
+
+// unpause drops the pause state when the host moves on without resuming, but
+// keeps the total quiz clock running from where it was frozen.
+func (h *Hub) unpause(ctx context.Context, instanceID string, quizData *models.QuizData) {
+	if _, paused := h.getPauseState(ctx, instanceID); !paused {
+		return
+	}
+	h.resumeRoomClock(ctx, instanceID, quizData)
+	h.clearPauseState(ctx, instanceID)
+}
+
+func (h *Hub) handleSkipQuestion(client *Client) {
+	ctx := context.Background()
+
+	quizData, err := h.getQuizData(ctx, client.InstanceID)
+	if err != nil {
+		log.Printf("Failed to get quiz data: %v", err)
+		client.SendError("Failed to skip question")
+		return
+	}
+
+	if quizData.QuizType != constants.QuizTypeSync {
+		client.SendError("Questions can only be skipped in sync quizzes")
+		return
+	}
+
+	questionIndex := h.currentQuestionIndex(ctx, client.InstanceID)
+	if h.questionStartTime(ctx, client.InstanceID, questionIndex) == 0 && h.redisClient != nil {
+		client.SendError("No question in progress")
+		return
+	}
+
+	h.cancelQuestionDeadline(questionDeadline{
+		InstanceID:    client.InstanceID,
+		QuestionIndex: questionIndex,
+	})
+	h.unpause(ctx, client.InstanceID, quizData)
+
+	if err := h.sessionRepo.AdvanceQuestionIndex(ctx, client.InstanceID, questionIndex+1); err != nil {
+		log.Printf("Failed to advance sessions past question %d: %v", questionIndex, err)
+		client.SendError("Failed to skip question")
+		return
+	}
+
+	log.Printf("Quiz %s: question %d skipped by host", client.InstanceID, questionIndex)
+
+	h.broadcastToInstance(client.InstanceID, MessageTypeQuestionSkipped, QuestionSkippedPayload{
+		QuestionIndex: questionIndex,
+	})
+	h.handleContinue(client)
+}
+
+func (h *Hub) handleEndQuizNow(client *Client) {
+	ctx := context.Background()
+
+	log.Printf("Quiz %s ended by host", client.InstanceID)
+
+	h.broadcastToInstance(client.InstanceID, MessageTypeQuizEnded, QuizEndedPayload{
+		Reason: constants.EndReasonHost,
+	})
+	if err := h.finishInstance(ctx, client.InstanceID); err != nil {
+		log.Printf("Failed to update instance status: %v", err)
+		client.SendError("Failed to end quiz")
+	}
+}
+
+// finishInstance stops every timer of the instance, marks it finished (or
+// pending_review when open answers need grading) and finishes every open
+// session. Results of a finished instance are published right
+// away; pending_review ones are published by quiz-service after grading.
+func (h *Hub) finishInstance(ctx context.Context, instanceID string) error {
+	h.cancelQuestionDeadline(questionDeadline{
+		InstanceID:    instanceID,
+		QuestionIndex: h.currentQuestionIndex(ctx, instanceID),
+	})
+	h.cancelQuestionDeadline(questionDeadline{
+		InstanceID: instanceID,
+		Total:      true,
+	})
+	h.cancelQuestionDeadline(questionDeadline{
+		InstanceID: instanceID,
+		Instance:   true,
+	})
+	h.clearPauseState(ctx, instanceID)
+
+	status := constants.InstanceStatusFinished
+	quizData, err := h.getQuizData(ctx, instanceID)
+	if err == nil && hasOpenQuestions(quizData) {
+		status = constants.InstanceStatusPendingReview
+	}
+
+	if err := h.updateInstanceStatus(ctx, instanceID, status); err != nil {
+		return err
+	}
+
+	if quizData != nil {
+		h.finishOpenSessions(ctx, quizData, instanceID)
+	}
+	h.saveTeamResults(ctx, instanceID)
+	if quizData != nil && status == constants.InstanceStatusFinished {
+		h.publishResultsReady(ctx, instanceID, quizData)
+	}
+
+	h.relay(&relayEnvelope{
+		InstanceID: instanceID,
+		Kind:       relayKindFinish,
+	})
+	return nil
+}
+
+func (h *Hub) handleKickParticipant(client *Client, payload any) {
+	ctx := context.Background()
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		client.SendError("Invalid kick format")
+		return
+	}
+
+	var kickPayload KickParticipantPayload
+	if err := json.Unmarshal(payloadBytes, &kickPayload); err != nil || kickPayload.UserID == "" {
+		client.SendError("Invalid kick format")
+		return
+	}
+
+	if kickPayload.UserID == client.UserID {
+		client.SendError("You cannot kick yourself")
+		return
+	}
+
+	if err := h.sessionRepo.UpdateSessionStatus(ctx, client.InstanceID, kickPayload.UserID, constants.SessionStatusKicked); err != nil {
+		log.Printf("Failed to kick user %s: %v", kickPayload.UserID, err)
+		client.SendError("Participant not found")
+		return
+	}
+
+	log.Printf("User %s kicked from instance %s", kickPayload.UserID, client.InstanceID)
+	h.invalidateLeaderboard(ctx, client.InstanceID)
+
+	h.broadcastToInstance(client.InstanceID, MessageTypeParticipantKicked, ParticipantKickedPayload{
+		UserID: kickPayload.UserID,
+	})
+	h.relay(&relayEnvelope{
+		InstanceID: client.InstanceID,
+		Kind:       relayKindKick,
+		UserID:     kickPayload.UserID,
+	})
+
+	quizData, err := h.getQuizData(ctx, client.InstanceID)
+	if err != nil || quizData.QuizType != constants.QuizTypeSync {
+		return
+	}
+
+	questionIndex := h.currentQuestionIndex(ctx, client.InstanceID)
+	if h.questionStartTime(ctx, client.InstanceID, questionIndex) == 0 {
+		return
+	}
+	if _, paused := h.getPauseState(ctx, client.InstanceID); paused {
+		return
+	}
+
+	if h.checkAllParticipantsAnswered(ctx, client.InstanceID, questionIndex) {
+		h.cancelQuestionDeadline(questionDeadline{
+			InstanceID:    client.InstanceID,
+			QuestionIndex: questionIndex,
+		})
+		h.showLeaderboardAndWait(client.InstanceID, questionIndex)
+	} else {
+		h.notifyCreatorProgress(ctx, client.InstanceID, questionIndex)
+	}
+}
+
+// disconnectLocalUser closes every socket the user holds on this replica once
+// pending messages have had a chance to flush.
+func (h *Hub) disconnectLocalUser(instanceID, userID string) {
+	for _, c := range h.localClients(instanceID) {
+		if c.UserID != userID {
+			continue
+		}
+		go func(c *Client) {
+			time.Sleep(500 * time.Millisecond)
+			h.Unregister <- c
+		}(c)
+	}
+}
+
+func hasOpenQuestions(quizData *models.QuizData) bool {
+	for _, q := range quizData.Questions {
+		if q.Type == constants.QuestionTypeOpen {
+			return true
+		}
+	}
+	return false
+}
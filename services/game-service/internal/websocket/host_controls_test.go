@@ -0,0 +1,113 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"game-service/internal/models"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestPauseFreezesRemainingTime(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	ctx := context.Background()
+
+	quizData := &models.QuizData{
+		QuizType:  "sync",
+		CreatedBy: "creator",
+		Questions: []models.Question{{ID: "q1", TimeLimitSec: 10}},
+	}
+	if err := hub.cacheQuizData(ctx, "inst-1", quizData); err != nil {
+		t.Fatalf("failed to cache quiz data: %v", err)
+	}
+
+	startTimeKey := "quiz:inst-1:question:0:start"
+	hub.redisClient.Set(ctx, startTimeKey, time.Now().Add(-4*time.Second).UnixMilli(), time.Hour)
+	hub.startInstanceQuestionTimer("inst-1", 0, 6*time.Second)
+
+	creator := addTestClient(hub, "inst-1", "creator", true)
+	participant := addTestClient(hub, "inst-1", "user-a", false)
+
+	hub.handlePauseQuiz(creator)
+
+	expectMessage(t, creator, MessageTypeQuizPaused)
+	msg := expectMessage(t, participant, MessageTypeQuizPaused)
+	payload, _ := msg.Payload.(map[string]any)
+	remaining, _ := payload["remaining_ms"].(float64)
+	if remaining < 5500 || remaining > 6000 {
+		t.Fatalf("remaining_ms = %v, want about 6000", payload["remaining_ms"])
+	}
+
+	if got := hub.fireDueDeadlines(ctx, time.Now().Add(time.Minute)); got != 0 {
+		t.Fatalf("fired %d deadlines while paused, want 0", got)
+	}
+
+	state, paused := hub.getPauseState(ctx, "inst-1")
+	if !paused {
+		t.Fatal("pause state was not stored")
+	}
+	hub.resumeQuestionTimer(ctx, "inst-1", quizData, state)
+
+	start := hub.questionStartTime(ctx, "inst-1", 0)
+	elapsed := time.Now().UnixMilli() - start
+	if elapsed < 4000 || elapsed > 4500 {
+		t.Fatalf("elapsed after resume = %dms, want about 4000", elapsed)
+	}
+
+	deadline, err := hub.redisClient.GetClient().ZScore(ctx, questionDeadlinesKey, questionDeadline{
+		InstanceID: "inst-1",
+	}.member()).Result()
+	if err != nil {
+		t.Fatalf("deadline was not rescheduled: %v", err)
+	}
+	if left := int64(deadline) - time.Now().UnixMilli(); left < 5000 || left > 6000 {
+		t.Fatalf("deadline due in %dms after resume, want about 6000", left)
+	}
+}
+
+func TestPauseAfterQuestionTimedOutIsRejected(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	ctx := context.Background()
+
+	quizData := &models.QuizData{
+		QuizType:  "sync",
+		CreatedBy: "creator",
+		Questions: []models.Question{{ID: "q1", TimeLimitSec: 10}},
+	}
+	if err := hub.cacheQuizData(ctx, "inst-1", quizData); err != nil {
+		t.Fatalf("failed to cache quiz data: %v", err)
+	}
+
+	// The question started 12s ago and its deadline has already been claimed.
+	startTimeKey := "quiz:inst-1:question:0:start"
+	hub.redisClient.Set(ctx, startTimeKey, time.Now().Add(-12*time.Second).UnixMilli(), time.Hour)
+	hub.startInstanceQuestionTimer("inst-1", 0, 0)
+	hub.redisClient.GetClient().ZRem(ctx, questionDeadlinesKey, questionDeadline{InstanceID: "inst-1"}.member())
+
+	creator := addTestClient(hub, "inst-1", "creator", true)
+
+	hub.handlePauseQuiz(creator)
+	msg := expectMessage(t, creator, MessageTypeError)
+	if payload, _ := msg.Payload.(map[string]any); payload["message"] != "Question time is already up" {
+		t.Fatalf("pause error = %v", msg.Payload)
+	}
+	if _, paused := hub.getPauseState(ctx, "inst-1"); paused {
+		t.Fatal("pause state stored after the question timed out")
+	}
+
+	hub.handleResumePausedQuiz(creator)
+	expectMessage(t, creator, MessageTypeError)
+	if count, _ := hub.redisClient.GetClient().ZCard(ctx, questionDeadlinesKey).Result(); count != 0 {
+		t.Fatalf("pending deadlines after resume = %d, want 0", count)
+	}
+
+	// A pause stored with no time left must not fire the timeout again.
+	hub.resumeQuestionTimer(ctx, "inst-1", quizData, &pauseState{QuestionIndex: 0})
+	if count, _ := hub.redisClient.GetClient().ZCard(ctx, questionDeadlinesKey).Result(); count != 0 {
+		t.Fatalf("pending deadlines after resuming an expired question = %d, want 0", count)
+	}
+}
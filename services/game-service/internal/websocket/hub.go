@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +22,15 @@ type ClientMessage struct {
 	Message Message
 }
 
+type RabbitMQPublisher interface {
+	Publish(ctx context.Context, queueName string, body []byte) error
+}
+
+type UserLookup interface {
+	GetUsersByIDs(ctx context.Context, userIDs []string) (map[string]*pb.User, error)
+	GetGroupMembers(ctx context.Context, groupID, requesterID string) (string, []string, error)
+}
+
 type Hub struct {
 	clients       map[string]map[*Client]bool
 	Register      chan *Client
@@ -30,21 +38,31 @@ type Hub struct {
 	HandleMessage chan *ClientMessage
 
 	quizClient  *client.QuizClient
+	userLookup  UserLookup
 	redisClient *cache.RedisClient
 	sessionRepo *repository.SessionRepository
+	teamRepo    *repository.TeamRepository
 	db          *sql.DB
+	mqPublisher RabbitMQPublisher
+
+	// nodeID identifies this replica on the Redis relay channel.
+	nodeID string
 
 	mu sync.RWMutex
 
+	// questionTimers holds in-process timers, used only when question
+	// deadlines cannot be persisted to Redis.
 	questionTimers map[string]*time.Timer
 	timerMu        sync.Mutex
 }
 
 func NewHub(
 	quizClient *client.QuizClient,
+	userLookup UserLookup,
 	redisClient *cache.RedisClient,
 	sessionRepo *repository.SessionRepository,
 	db *sql.DB,
+	mqPublisher RabbitMQPublisher,
 ) *Hub {
 	return &Hub{
 		clients:        make(map[string]map[*Client]bool),
@@ -52,14 +70,24 @@ func NewHub(
 		Unregister:     make(chan *Client),
 		HandleMessage:  make(chan *ClientMessage),
 		quizClient:     quizClient,
+		userLookup:     userLookup,
 		redisClient:    redisClient,
 		sessionRepo:    sessionRepo,
+		teamRepo:       repository.NewTeamRepository(db),
 		db:             db,
+		mqPublisher:    mqPublisher,
+		nodeID:         newNodeID(),
 		questionTimers: make(map[string]*time.Timer),
 	}
 }
 
 func (h *Hub) Run() {
+	if err := h.startRelay(); err != nil {
+		log.Printf("Warning: Hub relay disabled, broadcasts stay local to this replica: %v", err)
+	}
+	go h.runDeadlineScheduler()
+	go h.runNodeHeartbeat()
+
 	for {
 		select {
 		case client := <-h.Register:
@@ -76,12 +104,19 @@ func (h *Hub) Run() {
 
 func (h *Hub) registerClient(client *Client) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	if h.clients[client.InstanceID] == nil {
 		h.clients[client.InstanceID] = make(map[*Client]bool)
 	}
 	h.clients[client.InstanceID][client] = true
+	h.mu.Unlock()
+
+	if client.IsSpectator {
+		log.Printf("Spectator registered: user=%s, instance=%s", client.UserID, client.InstanceID)
+		go h.handleSpectatorJoin(client)
+		return
+	}
+
+	h.trackConnection(context.Background(), client.InstanceID, client.UserID, 1)
 
 	log.Printf("Client registered: user=%s, instance=%s, isCreator=%v",
 		client.UserID, client.InstanceID, client.IsCreator)
@@ -91,30 +126,35 @@ func (h *Hub) registerClient(client *Client) {
 
 func (h *Hub) unregisterClient(client *Client) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	if clients, ok := h.clients[client.InstanceID]; ok {
-		if _, ok := clients[client]; ok {
-			delete(clients, client)
-			close(client.Send)
-
-			if len(clients) == 0 {
-				delete(h.clients, client.InstanceID)
-				h.cancelAllTimersForInstance(client.InstanceID)
-			} else {
-				payload := ParticipantsUpdatePayload{
-					Action: constants.ActionLeft,
-					UserID: client.UserID,
-					Count:  len(clients),
-				}
-				for c := range clients {
-					c.SendMessage(MessageTypeParticipantsUpdate, payload)
-				}
-			}
+	clients, ok := h.clients[client.InstanceID]
+	if !ok || !clients[client] {
+		h.mu.Unlock()
+		return
+	}
+	delete(clients, client)
+	close(client.Send)
+	if len(clients) == 0 {
+		delete(h.clients, client.InstanceID)
+	}
+	h.mu.Unlock()
 
-			log.Printf("Client unregistered: user=%s, instance=%s", client.UserID, client.InstanceID)
-		}
+	if client.IsSpectator {
+		log.Printf("Spectator unregistered: user=%s, instance=%s", client.UserID, client.InstanceID)
+		return
+	}
+
+	count := h.trackConnection(context.Background(), client.InstanceID, client.UserID, -1)
+	if count == 0 {
+		h.cancelAllTimersForInstance(client.InstanceID)
+	} else {
+		h.broadcastToInstance(client.InstanceID, MessageTypeParticipantsUpdate, ParticipantsUpdatePayload{
+			Action: constants.ActionLeft,
+			UserID: client.UserID,
+			Count:  count,
+		})
 	}
+
+	log.Printf("Client unregistered: user=%s, instance=%s", client.UserID, client.InstanceID)
 }
 
 func (h *Hub) handleClientMessage(clientMsg *ClientMessage) {
@@ -123,6 +163,11 @@ func (h *Hub) handleClientMessage(clientMsg *ClientMessage) {
 
 	log.Printf("Received message: type=%s, user=%s, instance=%s", msg.Type, client.UserID, client.InstanceID)
 
+	if client.IsSpectator && msg.Type != MessageTypePing {
+		client.SendError("Spectators can only watch the quiz")
+		return
+	}
+
 	switch msg.Type {
 	case MessageTypeStart:
 		if client.IsCreator {
@@ -141,6 +186,14 @@ func (h *Hub) handleClientMessage(clientMsg *ClientMessage) {
 			client.SendError("Only the creator can continue")
 		}
 
+	case MessageTypePauseQuiz, MessageTypeResumeQuiz, MessageTypeSkipQuestion, MessageTypeEndQuizNow, MessageTypeKickParticipant,
+		MessageTypeAssignTeams:
+		if !client.IsCreator {
+			client.SendError("Only the creator can control the quiz")
+			return
+		}
+		h.handleHostControl(client, msg)
+
 	case MessageTypePing:
 		client.SendMessage(MessageTypePong, nil)
 
@@ -149,6 +202,23 @@ func (h *Hub) handleClientMessage(clientMsg *ClientMessage) {
 	}
 }
 
+func (h *Hub) handleHostControl(client *Client, msg Message) {
+	switch msg.Type {
+	case MessageTypePauseQuiz:
+		h.handlePauseQuiz(client)
+	case MessageTypeResumeQuiz:
+		h.handleResumePausedQuiz(client)
+	case MessageTypeSkipQuestion:
+		h.handleSkipQuestion(client)
+	case MessageTypeEndQuizNow:
+		h.handleEndQuizNow(client)
+	case MessageTypeKickParticipant:
+		h.handleKickParticipant(client, msg.Payload)
+	case MessageTypeAssignTeams:
+		h.handleAssignTeams(client, msg.Payload)
+	}
+}
+
 func (h *Hub) handleJoin(client *Client) {
 	log.Printf("Handling join for user %s in instance %s", client.UserID, client.InstanceID)
 	ctx := context.Background()
@@ -161,7 +231,7 @@ func (h *Hub) handleJoin(client *Client) {
 	}
 	log.Printf("Successfully retrieved quiz instance %s", client.InstanceID)
 
-	if quizResp.Instance.Status == constants.InstanceStatusFinished {
+	if isInstanceClosed(quizResp.Instance.Status) {
 		log.Printf("Quiz %s is finished, rejecting connection for user %s", client.InstanceID, client.UserID)
 		client.SendError("Quiz has already finished")
 
@@ -177,6 +247,18 @@ func (h *Hub) handleJoin(client *Client) {
 		log.Printf("Failed to cache quiz data: %v", err)
 	}
 
+	if quizData.QuizType == constants.QuizTypeAsync && quizResp.Instance.Deadline != nil &&
+		!h.scheduleInstanceDeadline(client.InstanceID, quizResp.Instance.Deadline.AsTime()) {
+		log.Printf("Quiz %s is past its deadline, rejecting connection for user %s", client.InstanceID, client.UserID)
+		client.SendError("Quiz deadline has passed")
+
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			h.Unregister <- client
+		}()
+		return
+	}
+
 	exists, err := h.sessionRepo.SessionExists(ctx, client.InstanceID, client.UserID)
 	if err != nil {
 		log.Printf("Failed to check session existence: %v", err)
@@ -184,7 +266,19 @@ func (h *Hub) handleJoin(client *Client) {
 		return
 	}
 
-	if !exists {
+	if exists {
+		session, err := h.sessionRepo.GetSession(ctx, client.InstanceID, client.UserID)
+		if err == nil && session.Status == constants.SessionStatusKicked {
+			log.Printf("User %s was kicked from quiz %s, rejecting connection", client.UserID, client.InstanceID)
+			client.SendError("You have been removed from this quiz")
+
+			go func() {
+				time.Sleep(500 * time.Millisecond)
+				h.Unregister <- client
+			}()
+			return
+		}
+	} else {
 		session := &models.GameSession{
 			InstanceID:           client.InstanceID,
 			UserID:               client.UserID,
@@ -194,11 +288,16 @@ func (h *Hub) handleJoin(client *Client) {
 			Answers:              "[]",
 			StartedAt:            time.Now(),
 		}
+		if order := newShuffleOrder(quizData, client.InstanceID, client.UserID); order != nil {
+			orderJSON, _ := json.Marshal(order)
+			session.QuestionOrder = string(orderJSON)
+		}
 		if err := h.sessionRepo.CreateSession(ctx, session); err != nil {
 			log.Printf("Failed to create session: %v", err)
 			client.SendError("Failed to join quiz")
 			return
 		}
+		h.invalidateLeaderboard(ctx, client.InstanceID)
 	}
 
 	log.Printf("Sending connected message to user %s (status=%s)", client.UserID, quizResp.Instance.Status)
@@ -209,9 +308,7 @@ func (h *Hub) handleJoin(client *Client) {
 		IsCreator:  client.IsCreator,
 	})
 
-	h.mu.RLock()
-	participantCount := len(h.clients[client.InstanceID])
-	h.mu.RUnlock()
+	participantCount := h.connectionCount(ctx, client.InstanceID)
 
 	h.broadcastToInstance(client.InstanceID, MessageTypeParticipantsUpdate, ParticipantsUpdatePayload{
 		Action: constants.ActionJoined,
@@ -254,9 +351,17 @@ func (h *Hub) convertToQuizData(resp *pb.GetInstanceResponse) *models.QuizData {
 		settings.TimeLimitTotal = int(resp.Instance.Settings.TimeLimitTotal)
 		settings.ShowCorrectAnswers = resp.Instance.Settings.ShowCorrectAnswers
 		settings.AllowReview = resp.Instance.Settings.AllowReview
+		settings.ShuffleOptions = resp.Instance.Settings.ShuffleOptions
+		settings.ScoringStrategy = resp.Instance.Settings.ScoringStrategy
+		settings.ScoringFloorPercent = int(resp.Instance.Settings.ScoringFloorPercent)
+		settings.ScoringPenaltyPercent = int(resp.Instance.Settings.ScoringPenaltyPercent)
+		settings.StreakBonusPercent = int(resp.Instance.Settings.StreakBonusPercent)
+		settings.StreakBonusMaxPercent = int(resp.Instance.Settings.StreakBonusMaxPercent)
+		settings.LeaderboardRanking = resp.Instance.Settings.LeaderboardRanking
 	}
 
 	return &models.QuizData{
+		Title:      resp.Instance.Title,
 		QuizType:   resp.Instance.QuizType,
 		CreatedBy:  resp.Instance.CreatedBy,
 		Questions:  questions,
@@ -295,25 +400,19 @@ func (h *Hub) getQuizData(ctx context.Context, instanceID string) (*models.QuizD
 }
 
 func (h *Hub) broadcastToInstance(instanceID string, msgType MessageType, payload interface{}) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	clients := h.clients[instanceID]
-	for client := range clients {
-		client.SendMessage(msgType, payload)
-	}
+	h.relayMessage(instanceID, relayTargetAll, msgType, payload)
 }
 
 func (h *Hub) broadcastToParticipants(instanceID string, msgType MessageType, payload interface{}) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	h.relayMessage(instanceID, relayTargetParticipants, msgType, payload)
+}
 
-	clients := h.clients[instanceID]
-	for client := range clients {
-		if !client.IsCreator {
-			client.SendMessage(msgType, payload)
-		}
-	}
+func (h *Hub) sendToCreator(instanceID string, msgType MessageType, payload interface{}) {
+	h.relayMessage(instanceID, relayTargetCreator, msgType, payload)
+}
+
+func (h *Hub) broadcastToSpectators(instanceID string, msgType MessageType, payload interface{}) {
+	h.relayMessage(instanceID, relayTargetSpectators, msgType, payload)
 }
 
 func (h *Hub) cancelQuestionTimer(timerKey string) {
@@ -338,30 +437,6 @@ func (h *Hub) cancelAllTimersForInstance(instanceID string) {
 	}
 }
 
-func (h *Hub) validateAnswer(answer, correctAnswerJSON string) bool {
-	var correctAnswer any
-	if err := json.Unmarshal([]byte(correctAnswerJSON), &correctAnswer); err != nil {
-		return strings.TrimSpace(strings.ToLower(answer)) == strings.TrimSpace(strings.ToLower(correctAnswerJSON))
-	}
-
-	correctAnswerStr := fmt.Sprintf("%v", correctAnswer)
-	return strings.TrimSpace(strings.ToLower(answer)) == strings.TrimSpace(strings.ToLower(correctAnswerStr))
-}
-
-func (h *Hub) calculateScore(maxScore int, timeSpentMs, timeLimitMs int64) int {
-	if timeLimitMs == 0 {
-		return maxScore
-	}
-
-	timeRatio := float64(timeSpentMs) / float64(timeLimitMs)
-	if timeRatio > 1.0 {
-		timeRatio = 1.0
-	}
-
-	score := float64(maxScore) * (1.0 - 0.5*timeRatio)
-	return int(score)
-}
-
 func (h *Hub) updateInstanceStatus(ctx context.Context, instanceID, status string) error {
 	query := `UPDATE quiz_instances SET status = $1 WHERE id = $2`
 	_, err := h.db.ExecContext(ctx, query, status, instanceID)
@@ -372,3 +447,11 @@ func (h *Hub) updateInstanceStatus(ctx context.Context, instanceID, status strin
 	log.Printf("Updated instance %s status to %s", instanceID, status)
 	return nil
 }
+
+func isInstanceClosed(status string) bool {
+	switch status {
+	case constants.InstanceStatusFinished, constants.InstanceStatusPendingReview, constants.InstanceStatusReviewed:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,267 @@
+package websocket
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const leaderboardTTL = 24 * time.Hour
+
+// leaderboardTimeScale packs score and total answer time into one sorted set
+// score: a higher score ranks first and less time spent breaks ties. Answer
+// times are capped just below it.
+const leaderboardTimeScale = 1e9
+
+func leaderboardKey(instanceID string) string {
+	return fmt.Sprintf("quiz:%s:leaderboard", instanceID)
+}
+
+// leaderboardEntriesKey holds the entry behind each member of the sorted set.
+func leaderboardEntriesKey(instanceID string) string {
+	return fmt.Sprintf("quiz:%s:leaderboard:entries", instanceID)
+}
+
+func leaderboardScore(entry LeaderboardEntry) float64 {
+	return float64(entry.Score)*leaderboardTimeScale - float64(min(entry.TotalTimeMs, leaderboardTimeScale-1))
+}
+
+func (h *Hub) getLeaderboard(ctx context.Context, instanceID string) []LeaderboardEntry {
+	quizData, err := h.getQuizData(ctx, instanceID)
+	if err != nil {
+		log.Printf("Failed to get quiz data: %v", err)
+		return []LeaderboardEntry{}
+	}
+
+	leaderboard, ok := h.cachedLeaderboard(ctx, instanceID)
+	if !ok {
+		var complete bool
+		leaderboard, complete, err = h.buildLeaderboard(ctx, instanceID, quizData.CreatedBy)
+		if err != nil {
+			log.Printf("Failed to get sessions for leaderboard: %v", err)
+			return []LeaderboardEntry{}
+		}
+		if complete {
+			h.cacheLeaderboard(ctx, instanceID, leaderboard)
+		}
+	}
+
+	rankLeaderboard(leaderboard, quizData.Settings.LeaderboardRanking)
+	return leaderboard
+}
+
+// buildLeaderboard reads the leaderboard from Postgres, sorted but not yet
+// ranked. complete is false when participant profiles could not be loaded.
+func (h *Hub) buildLeaderboard(ctx context.Context, instanceID, createdBy string) ([]LeaderboardEntry, bool, error) {
+	sessions, err := h.sessionRepo.GetSessionsByInstance(ctx, instanceID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var leaderboard []LeaderboardEntry
+	for _, session := range sessions {
+		if session.UserID == createdBy || session.Status == constants.SessionStatusKicked {
+			continue
+		}
+		leaderboard = append(leaderboard, leaderboardEntry(session))
+	}
+
+	complete := h.addUserProfiles(ctx, leaderboard)
+	sortLeaderboard(leaderboard)
+	return leaderboard, complete, nil
+}
+
+func leaderboardEntry(session *models.GameSession) LeaderboardEntry {
+	entry := LeaderboardEntry{
+		UserID:     session.UserID,
+		TeamID:     session.TeamID,
+		Score:      session.Score,
+		Streak:     session.CurrentStreak,
+		BestStreak: session.BestStreak,
+	}
+
+	var answers []models.Answer
+	if err := json.Unmarshal([]byte(session.Answers), &answers); err == nil {
+		for _, a := range answers {
+			entry.TotalTimeMs += a.TimeSpentMs
+		}
+	}
+	return entry
+}
+
+// addUserProfiles fills in names and avatars with one user-service call.
+func (h *Hub) addUserProfiles(ctx context.Context, leaderboard []LeaderboardEntry) bool {
+	if len(leaderboard) == 0 {
+		return true
+	}
+	if h.userLookup == nil {
+		return false
+	}
+
+	userIDs := make([]string, len(leaderboard))
+	for i, entry := range leaderboard {
+		userIDs[i] = entry.UserID
+	}
+
+	users, err := h.userLookup.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		log.Printf("Failed to get participant profiles: %v", err)
+		return false
+	}
+
+	for i := range leaderboard {
+		if user, ok := users[leaderboard[i].UserID]; ok {
+			leaderboard[i].FirstName = user.FirstName
+			leaderboard[i].LastName = user.LastName
+			leaderboard[i].AvatarURL = user.AvatarUrl
+		}
+	}
+	return true
+}
+
+func sortLeaderboard(leaderboard []LeaderboardEntry) {
+	slices.SortFunc(leaderboard, func(a, b LeaderboardEntry) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(a.TotalTimeMs, b.TotalTimeMs),
+			cmp.Compare(a.UserID, b.UserID),
+		)
+	})
+}
+
+// rankLeaderboard ranks a sorted leaderboard. Entries with the same score and
+// total answer time share a rank. Competition ranking then skips the ranks
+// they took (1, 1, 3); dense ranking does not (1, 1, 2).
+func rankLeaderboard(leaderboard []LeaderboardEntry, ranking string) {
+	for i := range leaderboard {
+		if i == 0 {
+			leaderboard[i].Rank = 1
+			continue
+		}
+		prev := leaderboard[i-1]
+		tied := leaderboard[i].Score == prev.Score && leaderboard[i].TotalTimeMs == prev.TotalTimeMs
+		leaderboard[i].Rank = nextRank(prev.Rank, i, tied, ranking)
+	}
+}
+
+// nextRank returns the rank of the i-th entry of a sorted list, given the rank
+// of the entry before it.
+func nextRank(prevRank, i int, tied bool, ranking string) int {
+	switch {
+	case tied:
+		return prevRank
+	case ranking == constants.RankingDense:
+		return prevRank + 1
+	}
+	return i + 1
+}
+
+func (h *Hub) cachedLeaderboard(ctx context.Context, instanceID string) ([]LeaderboardEntry, bool) {
+	if h.redisClient == nil {
+		return nil, false
+	}
+	rdb := h.redisClient.GetClient()
+
+	userIDs, err := rdb.ZRevRange(ctx, leaderboardKey(instanceID), 0, -1).Result()
+	if err != nil || len(userIDs) == 0 {
+		return nil, false
+	}
+
+	values, err := rdb.HMGet(ctx, leaderboardEntriesKey(instanceID), userIDs...).Result()
+	if err != nil {
+		log.Printf("Failed to read cached leaderboard: %v", err)
+		return nil, false
+	}
+
+	leaderboard := make([]LeaderboardEntry, 0, len(values))
+	for _, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		var entry LeaderboardEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return nil, false
+		}
+		leaderboard = append(leaderboard, entry)
+	}
+	return leaderboard, true
+}
+
+func (h *Hub) cacheLeaderboard(ctx context.Context, instanceID string, leaderboard []LeaderboardEntry) {
+	if h.redisClient == nil || len(leaderboard) == 0 {
+		return
+	}
+	key, entriesKey := leaderboardKey(instanceID), leaderboardEntriesKey(instanceID)
+
+	pipe := h.redisClient.GetClient().TxPipeline()
+	pipe.Del(ctx, key, entriesKey)
+	for _, entry := range leaderboard {
+		data, _ := json.Marshal(entry)
+		pipe.ZAdd(ctx, key, redis.Z{Score: leaderboardScore(entry), Member: entry.UserID})
+		pipe.HSet(ctx, entriesKey, entry.UserID, data)
+	}
+	pipe.Expire(ctx, key, leaderboardTTL)
+	pipe.Expire(ctx, entriesKey, leaderboardTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Failed to cache leaderboard for instance %s: %v", instanceID, err)
+	}
+}
+
+// updateLeaderboardEntry moves a participant in the cached leaderboard after
+// their session changed. A leaderboard that is not cached is left to be built
+// from Postgres on the next read.
+func (h *Hub) updateLeaderboardEntry(ctx context.Context, session *models.GameSession) {
+	if h.redisClient == nil {
+		return
+	}
+	rdb := h.redisClient.GetClient()
+	key, entriesKey := leaderboardKey(session.InstanceID), leaderboardEntriesKey(session.InstanceID)
+
+	data, err := rdb.HGet(ctx, entriesKey, session.UserID).Result()
+	if err != nil {
+		return
+	}
+	var cached LeaderboardEntry
+	if err := json.Unmarshal([]byte(data), &cached); err != nil {
+		h.invalidateLeaderboard(ctx, session.InstanceID)
+		return
+	}
+
+	entry := leaderboardEntry(session)
+	entry.FirstName = cached.FirstName
+	entry.LastName = cached.LastName
+	entry.AvatarURL = cached.AvatarURL
+	entryData, _ := json.Marshal(entry)
+
+	// XX keeps a leaderboard that was dropped meanwhile from coming back with
+	// a single member.
+	pipe := rdb.TxPipeline()
+	pipe.ZAddXX(ctx, key, redis.Z{Score: leaderboardScore(entry), Member: entry.UserID})
+	pipe.HSet(ctx, entriesKey, entry.UserID, entryData)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Failed to update cached leaderboard for instance %s: %v", session.InstanceID, err)
+		h.invalidateLeaderboard(ctx, session.InstanceID)
+	}
+}
+
+// invalidateLeaderboard drops the cached leaderboard when participants join or
+// leave it.
+func (h *Hub) invalidateLeaderboard(ctx context.Context, instanceID string) {
+	if h.redisClient == nil {
+		return
+	}
+	if err := h.redisClient.Delete(ctx, leaderboardKey(instanceID), leaderboardEntriesKey(instanceID)); err != nil {
+		log.Printf("Failed to invalidate leaderboard for instance %s: %v", instanceID, err)
+	}
+}
@@ -0,0 +1,124 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"game-service/internal/models"
+	pb "game-service/proto"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func ranks(leaderboard []LeaderboardEntry) []int {
+	result := make([]int, len(leaderboard))
+	for i, entry := range leaderboard {
+		result[i] = entry.Rank
+	}
+	return result
+}
+
+func TestRankLeaderboardTies(t *testing.T) {
+	leaderboard := []LeaderboardEntry{
+		{UserID: "d", Score: 50, TotalTimeMs: 9000},
+		{UserID: "a", Score: 100, TotalTimeMs: 5000},
+		{UserID: "c", Score: 100, TotalTimeMs: 4000},
+		{UserID: "b", Score: 100, TotalTimeMs: 5000},
+	}
+	sortLeaderboard(leaderboard)
+
+	order := []string{leaderboard[0].UserID, leaderboard[1].UserID, leaderboard[2].UserID, leaderboard[3].UserID}
+	if want := []string{"c", "a", "b", "d"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+
+	rankLeaderboard(leaderboard, "competition")
+	if got, want := ranks(leaderboard), []int{1, 2, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("competition ranks = %v, want %v", got, want)
+	}
+
+	rankLeaderboard(leaderboard, "dense")
+	if got, want := ranks(leaderboard), []int{1, 2, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("dense ranks = %v, want %v", got, want)
+	}
+}
+
+func TestLeaderboardEntrySumsAnswerTime(t *testing.T) {
+	answers, _ := json.Marshal([]models.Answer{{TimeSpentMs: 1200}, {TimeSpentMs: 800}})
+	entry := leaderboardEntry(&models.GameSession{UserID: "a", Score: 7, Answers: string(answers), CurrentStreak: 2, BestStreak: 3})
+
+	if entry.TotalTimeMs != 2000 || entry.Score != 7 || entry.Streak != 2 || entry.BestStreak != 3 {
+		t.Fatalf("entry = %+v", entry)
+	}
+}
+
+type fakeUserLookup struct {
+	users  map[string]*pb.User
+	groups map[string][]string
+	err    error
+}
+
+func (f *fakeUserLookup) GetUsersByIDs(ctx context.Context, userIDs []string) (map[string]*pb.User, error) {
+	return f.users, f.err
+}
+
+func (f *fakeUserLookup) GetGroupMembers(ctx context.Context, groupID, requesterID string) (string, []string, error) {
+	members, ok := f.groups[groupID]
+	if !ok {
+		return "", nil, errors.New("group not found")
+	}
+	return "Group " + groupID, members, nil
+}
+
+func TestAddUserProfiles(t *testing.T) {
+	hub := &Hub{userLookup: &fakeUserLookup{users: map[string]*pb.User{
+		"a": {Id: "a", FirstName: "Ada", LastName: "Lovelace", AvatarUrl: "http://avatars/a.png"},
+	}}}
+	leaderboard := []LeaderboardEntry{{UserID: "a"}, {UserID: "gone"}}
+
+	if !hub.addUserProfiles(context.Background(), leaderboard) {
+		t.Fatal("profiles not loaded")
+	}
+	if leaderboard[0].FirstName != "Ada" || leaderboard[0].LastName != "Lovelace" || leaderboard[0].AvatarURL != "http://avatars/a.png" {
+		t.Fatalf("entry = %+v", leaderboard[0])
+	}
+	if leaderboard[1].FirstName != "" {
+		t.Fatalf("unknown user got a profile: %+v", leaderboard[1])
+	}
+
+	hub.userLookup = &fakeUserLookup{err: errors.New("unavailable")}
+	if hub.addUserProfiles(context.Background(), leaderboard) {
+		t.Fatal("failed lookup reported as complete")
+	}
+}
+
+func TestCachedLeaderboardFollowsAnswers(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	ctx := context.Background()
+
+	hub.cacheLeaderboard(ctx, "inst-1", []LeaderboardEntry{
+		{UserID: "a", FirstName: "Ada", Score: 10, TotalTimeMs: 3000},
+		{UserID: "b", FirstName: "Bob", Score: 5, TotalTimeMs: 1000},
+	})
+
+	answers, _ := json.Marshal([]models.Answer{{TimeSpentMs: 1000}, {TimeSpentMs: 1500}})
+	hub.updateLeaderboardEntry(ctx, &models.GameSession{InstanceID: "inst-1", UserID: "b", Score: 10, Answers: string(answers)})
+
+	leaderboard, ok := hub.cachedLeaderboard(ctx, "inst-1")
+	if !ok || len(leaderboard) != 2 {
+		t.Fatalf("cached leaderboard = %+v, %v", leaderboard, ok)
+	}
+	if leaderboard[0].UserID != "b" || leaderboard[0].FirstName != "Bob" || leaderboard[0].TotalTimeMs != 2500 {
+		t.Fatalf("faster player with the same score should lead: %+v", leaderboard)
+	}
+
+	hub.invalidateLeaderboard(ctx, "inst-1")
+	hub.updateLeaderboardEntry(ctx, &models.GameSession{InstanceID: "inst-1", UserID: "b", Score: 20, Answers: "[]"})
+	if _, ok := hub.cachedLeaderboard(ctx, "inst-1"); ok {
+		t.Fatal("update recreated a dropped leaderboard")
+	}
+}
@@ -10,6 +10,14 @@ const (
 	MessageTypeContinue MessageType = "continue"
 	MessageTypePing     MessageType = "ping"
 
+	// Client -> Server (creator only)
+	MessageTypePauseQuiz       MessageType = "pause_quiz"
+	MessageTypeResumeQuiz      MessageType = "resume_quiz"
+	MessageTypeSkipQuestion    MessageType = "skip_question"
+	MessageTypeEndQuizNow      MessageType = "end_quiz_now"
+	MessageTypeKickParticipant MessageType = "kick_participant"
+	MessageTypeAssignTeams     MessageType = "assign_teams"
+
 	// Server -> Client
 	MessageTypeConnected          MessageType = "connected"
 	MessageTypeParticipantsUpdate MessageType = "participants_update"
@@ -22,6 +30,15 @@ const (
 	MessageTypeQuizFinished       MessageType = "quiz_finished"
 	MessageTypeError              MessageType = "error"
 	MessageTypePong               MessageType = "pong"
+	MessageTypeQuizPaused         MessageType = "quiz_paused"
+	MessageTypeQuizResumed        MessageType = "quiz_resumed"
+	MessageTypeQuestionSkipped    MessageType = "question_skipped"
+	MessageTypeQuizEnded          MessageType = "quiz_ended"
+	MessageTypeParticipantKicked  MessageType = "participant_kicked"
+	MessageTypeTeamsAssigned      MessageType = "teams_assigned"
+	MessageTypeTeamLeaderboard    MessageType = "team_leaderboard"
+	MessageTypeAnswerHistogram    MessageType = "answer_histogram"
+	MessageTypeQuestionSummary    MessageType = "question_summary"
 )
 
 type Message struct {
@@ -40,11 +57,31 @@ type AnswerPayload struct {
 	TimeSpentMs int64  `json:"time_spent_ms,omitempty"`
 }
 
+type KickParticipantPayload struct {
+	UserID string `json:"user_id"`
+}
+
+// AssignTeamsPayload splits the joined participants into teams. Mode is
+// manual (Teams), random (TeamCount teams) or groups (a team per group).
+type AssignTeamsPayload struct {
+	Mode      string      `json:"mode"`
+	Teams     []TeamInput `json:"teams,omitempty"`
+	TeamCount int         `json:"team_count,omitempty"`
+	GroupIDs  []string    `json:"group_ids,omitempty"`
+}
+
+type TeamInput struct {
+	Name    string   `json:"name"`
+	UserIDs []string `json:"user_ids"`
+}
+
 type ConnectedPayload struct {
 	SessionID  string `json:"session_id"`
 	QuizType   string `json:"quiz_type"`
 	QuizStatus string `json:"quiz_status"`
 	IsCreator  bool   `json:"is_creator"`
+
+	IsSpectator bool `json:"is_spectator,omitempty"`
 }
 
 type ParticipantsUpdatePayload struct {
@@ -63,6 +100,8 @@ type QuestionPayload struct {
 	TotalQuestions int          `json:"total_questions"`
 	TimeLimitMs    int64        `json:"time_limit_ms,omitempty"`
 	ServerTime     int64        `json:"server_time"`
+
+	TotalRemainingMs int64 `json:"total_remaining_ms,omitempty"`
 }
 
 type QuestionData struct {
@@ -70,6 +109,7 @@ type QuestionData struct {
 	Text         string   `json:"text"`
 	Type         string   `json:"type"`
 	Options      []string `json:"options,omitempty"`
+	MatchOptions []string `json:"match_options,omitempty"` // right-hand items of a matching question
 	OrderIndex   int      `json:"order_index"`
 	MaxScore     int      `json:"max_score"`
 	TimeLimitSec int      `json:"time_limit_sec"`
@@ -80,6 +120,14 @@ type AnswerResultPayload struct {
 	Score       int   `json:"score"`
 	TimeSpentMs int64 `json:"time_spent_ms"`
 	TotalScore  int   `json:"total_score"`
+
+	CorrectAnswer string `json:"correct_answer,omitempty"`
+	PendingReview bool   `json:"pending_review,omitempty"`
+
+	// Streak is the streak after this answer; StreakMultiplier is the bonus
+	// multiplier that was applied to it.
+	Streak           int     `json:"streak"`
+	StreakMultiplier float64 `json:"streak_multiplier"`
 }
 
 type LeaderboardPayload struct {
@@ -87,9 +135,63 @@ type LeaderboardPayload struct {
 }
 
 type LeaderboardEntry struct {
-	Rank   int    `json:"rank"`
-	UserID string `json:"user_id"`
-	Score  int    `json:"score"`
+	Rank        int    `json:"rank"`
+	UserID      string `json:"user_id"`
+	TeamID      string `json:"team_id,omitempty"`
+	FirstName   string `json:"first_name,omitempty"`
+	LastName    string `json:"last_name,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	Score       int    `json:"score"`
+	TotalTimeMs int64  `json:"total_time_ms"`
+	Streak      int    `json:"streak"`
+	BestStreak  int    `json:"best_streak"`
+}
+
+type TeamsAssignedPayload struct {
+	Teams []TeamPayload `json:"teams"`
+}
+
+type TeamPayload struct {
+	TeamID  string   `json:"team_id"`
+	Name    string   `json:"name"`
+	UserIDs []string `json:"user_ids"`
+}
+
+type TeamLeaderboardPayload struct {
+	Teams []TeamLeaderboardEntry `json:"teams"`
+}
+
+type TeamLeaderboardEntry struct {
+	Rank         int     `json:"rank"`
+	TeamID       string  `json:"team_id"`
+	Name         string  `json:"name"`
+	Score        int     `json:"score"`
+	AverageScore float64 `json:"average_score"`
+	MemberCount  int     `json:"member_count"`
+}
+
+// AnswerHistogramPayload shows how the answers to a sync question are spread.
+// It is streamed as answers come in and sent once more as the question
+// summary. Buckets are the options of choice and true/false questions, in
+// their original order; other question types only report the counts.
+type AnswerHistogramPayload struct {
+	QuestionIndex  int               `json:"question_index"`
+	QuestionID     string            `json:"question_id"`
+	Answered       int               `json:"answered"`
+	Participants   int               `json:"participants"`
+	AverageTimeMs  int64             `json:"average_time_ms"`
+	CorrectPercent float64           `json:"correct_percent"`
+	PendingReview  int               `json:"pending_review,omitempty"`
+	Buckets        []HistogramBucket `json:"buckets,omitempty"`
+
+	// CorrectAnswer is only set on the question_summary sent once the
+	// question has closed.
+	CorrectAnswer string `json:"correct_answer,omitempty"`
+}
+
+type HistogramBucket struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
 }
 
 type TimeExpiredPayload struct {
@@ -106,6 +208,29 @@ type QuizFinishedPayload struct {
 	Rank       int `json:"rank"`
 }
 
+type QuizPausedPayload struct {
+	QuestionIndex int   `json:"question_index"`
+	RemainingMs   int64 `json:"remaining_ms,omitempty"`
+}
+
+type QuizResumedPayload struct {
+	QuestionIndex int   `json:"question_index"`
+	RemainingMs   int64 `json:"remaining_ms,omitempty"`
+	ServerTime    int64 `json:"server_time"`
+}
+
+type QuestionSkippedPayload struct {
+	QuestionIndex int `json:"question_index"`
+}
+
+type QuizEndedPayload struct {
+	Reason string `json:"reason"`
+}
+
+type ParticipantKickedPayload struct {
+	UserID string `json:"user_id"`
+}
+
 type ErrorPayload struct {
 	Message string `json:"message"`
 }
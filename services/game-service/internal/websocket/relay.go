@@ -0,0 +1,349 @@
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// hubRelayChannel is the Redis pub/sub channel every game-service replica
+// listens on, so a message for an instance reaches sockets on any pod.
+const hubRelayChannel = "game:hub:relay"
+
+type relayKind string
+
+const (
+	relayKindMessage         relayKind = "message"
+	relayKindFinish          relayKind = "finish"
+	relayKindQuestionTimeout relayKind = "question_timeout"
+	relayKindKick            relayKind = "kick"
+	relayKindSessionExpired  relayKind = "session_expired"
+	relayKindStart           relayKind = "start"
+)
+
+type relayTarget string
+
+const (
+	relayTargetAll          relayTarget = "all"
+	relayTargetParticipants relayTarget = "participants"
+	relayTargetCreator      relayTarget = "creator"
+	relayTargetSpectators   relayTarget = "spectators"
+)
+
+type relayEnvelope struct {
+	Origin     string          `json:"origin"`
+	InstanceID string          `json:"instance_id"`
+	Kind       relayKind       `json:"kind"`
+	Target     relayTarget     `json:"target,omitempty"`
+	Message    json.RawMessage `json:"message,omitempty"`
+
+	UserID        string `json:"user_id,omitempty"`
+	QuestionIndex int    `json:"question_index,omitempty"`
+}
+
+func (t relayTarget) matches(c *Client) bool {
+	switch t {
+	case relayTargetParticipants:
+		return !c.IsCreator && !c.IsSpectator
+	case relayTargetCreator:
+		return c.IsCreator
+	case relayTargetSpectators:
+		return c.IsSpectator
+	default:
+		return true
+	}
+}
+
+func newNodeID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// startRelay subscribes to the relay channel and blocks until Redis confirms
+// the subscription, then consumes messages in the background.
+func (h *Hub) startRelay() error {
+	if h.redisClient == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+	pubsub := h.redisClient.Subscribe(ctx, hubRelayChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe to %s: %w", hubRelayChannel, err)
+	}
+
+	go func() {
+		defer pubsub.Close()
+		for msg := range pubsub.Channel() {
+			var env relayEnvelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				log.Printf("Failed to unmarshal relay message: %v", err)
+				continue
+			}
+			if env.Origin == h.nodeID {
+				continue
+			}
+			h.deliverLocal(&env)
+		}
+	}()
+
+	h.beatNode(ctx, time.Now())
+	log.Printf("Hub relay subscribed: node=%s, channel=%s", h.nodeID, hubRelayChannel)
+	return nil
+}
+
+func (h *Hub) relay(env *relayEnvelope) {
+	env.Origin = h.nodeID
+	h.deliverLocal(env)
+
+	if h.redisClient == nil {
+		return
+	}
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		log.Printf("Failed to marshal relay message: %v", err)
+		return
+	}
+	if err := h.redisClient.Publish(context.Background(), hubRelayChannel, data); err != nil {
+		log.Printf("Failed to publish relay message for instance %s: %v", env.InstanceID, err)
+	}
+}
+
+func (h *Hub) relayMessage(instanceID string, target relayTarget, msgType MessageType, payload any) {
+	data, err := json.Marshal(Message{Type: msgType, Payload: payload})
+	if err != nil {
+		log.Printf("Failed to marshal message: %v", err)
+		return
+	}
+
+	h.relay(&relayEnvelope{
+		InstanceID: instanceID,
+		Kind:       relayKindMessage,
+		Target:     target,
+		Message:    data,
+	})
+}
+
+func (h *Hub) deliverLocal(env *relayEnvelope) {
+	switch env.Kind {
+	case relayKindMessage:
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+
+		for c := range h.clients[env.InstanceID] {
+			if env.Target.matches(c) {
+				c.sendRaw(env.Message)
+			}
+		}
+
+	case relayKindFinish:
+		for _, c := range h.localClients(env.InstanceID) {
+			h.finishQuiz(c)
+		}
+		for _, c := range h.localSpectators(env.InstanceID) {
+			h.finishSpectator(c)
+		}
+
+	case relayKindQuestionTimeout:
+		h.handleLocalQuestionTimeout(env.InstanceID, env.UserID, env.QuestionIndex)
+
+	case relayKindKick:
+		h.disconnectLocalUser(env.InstanceID, env.UserID)
+
+	case relayKindSessionExpired:
+		h.finishLocalSession(env.InstanceID, env.UserID)
+
+	case relayKindStart:
+		h.startLocalClients(env.InstanceID)
+	}
+}
+
+// localClients returns the sockets of the creator and participants on this
+// replica. Spectators only receive relayed messages and are left out.
+func (h *Hub) localClients(instanceID string) []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	clients := make([]*Client, 0, len(h.clients[instanceID]))
+	for c := range h.clients[instanceID] {
+		if !c.IsSpectator {
+			clients = append(clients, c)
+		}
+	}
+	return clients
+}
+
+func (h *Hub) localSpectators(instanceID string) []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var spectators []*Client
+	for c := range h.clients[instanceID] {
+		if c.IsSpectator {
+			spectators = append(spectators, c)
+		}
+	}
+	return spectators
+}
+
+// liveNodesKey scores every replica by its last heartbeat. Connection counts
+// held by a replica that stopped beating, e.g. after a crash, are ignored.
+const (
+	liveNodesKey          = "game:nodes"
+	nodeHeartbeatInterval = 10 * time.Second
+	nodeTTL               = 3 * nodeHeartbeatInterval
+)
+
+func connectionsKey(instanceID string) string {
+	return fmt.Sprintf("quiz:%s:connections", instanceID)
+}
+
+// connectionField keys a user's socket count by the replica holding the
+// sockets, so each replica only ever adjusts its own entries.
+func connectionField(nodeID, userID string) string {
+	return nodeID + ":" + userID
+}
+
+func (h *Hub) beatNode(ctx context.Context, now time.Time) {
+	rdb := h.redisClient.GetClient()
+	if err := rdb.ZAdd(ctx, liveNodesKey, redis.Z{Score: float64(now.UnixMilli()), Member: h.nodeID}).Err(); err != nil {
+		log.Printf("Failed to record heartbeat for node %s: %v", h.nodeID, err)
+		return
+	}
+	rdb.ZRemRangeByScore(ctx, liveNodesKey, "-inf", strconv.FormatInt(now.Add(-nodeTTL).UnixMilli(), 10))
+}
+
+func (h *Hub) runNodeHeartbeat() {
+	if h.redisClient == nil {
+		return
+	}
+
+	ticker := time.NewTicker(nodeHeartbeatInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.beatNode(context.Background(), time.Now())
+	}
+}
+
+// liveNodes returns the replicas whose heartbeat is within nodeTTL of now.
+func (h *Hub) liveNodes(ctx context.Context, now time.Time) (map[string]bool, error) {
+	nodes, err := h.redisClient.GetClient().ZRangeByScore(ctx, liveNodesKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(now.Add(-nodeTTL).UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		live[node] = true
+	}
+	return live, nil
+}
+
+// trackConnection adjusts the number of sockets a user holds for an instance
+// on this replica and returns how many distinct users are connected
+// cluster-wide afterwards.
+func (h *Hub) trackConnection(ctx context.Context, instanceID, userID string, delta int64) int {
+	if h.redisClient == nil {
+		return h.localConnectionCount(instanceID)
+	}
+
+	rdb := h.redisClient.GetClient()
+	key := connectionsKey(instanceID)
+	field := connectionField(h.nodeID, userID)
+
+	n, err := rdb.HIncrBy(ctx, key, field, delta).Result()
+	if err != nil {
+		log.Printf("Failed to track connection for user %s in instance %s: %v", userID, instanceID, err)
+		return h.localConnectionCount(instanceID)
+	}
+	if n <= 0 {
+		rdb.HDel(ctx, key, field)
+	}
+	rdb.Expire(ctx, key, 24*time.Hour)
+
+	return h.connectionCount(ctx, instanceID)
+}
+
+func (h *Hub) connectionCount(ctx context.Context, instanceID string) int {
+	return len(h.connectedUserIDs(ctx, instanceID))
+}
+
+// connectedUserIDs lists users holding at least one socket for the instance
+// on any live replica. Entries left behind by dead replicas are dropped.
+func (h *Hub) connectedUserIDs(ctx context.Context, instanceID string) []string {
+	if h.redisClient != nil {
+		userIDs, err := h.clusterUserIDs(ctx, instanceID)
+		if err == nil {
+			return userIDs
+		}
+		log.Printf("Failed to list connections for instance %s: %v", instanceID, err)
+	}
+
+	seen := make(map[string]bool)
+	var userIDs []string
+	for _, c := range h.localClients(instanceID) {
+		if !seen[c.UserID] {
+			seen[c.UserID] = true
+			userIDs = append(userIDs, c.UserID)
+		}
+	}
+	return userIDs
+}
+
+func (h *Hub) clusterUserIDs(ctx context.Context, instanceID string) ([]string, error) {
+	rdb := h.redisClient.GetClient()
+	key := connectionsKey(instanceID)
+
+	counts, err := rdb.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	live, err := h.liveNodes(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	userIDs := make([]string, 0, len(counts))
+	var stale []string
+	for field, n := range counts {
+		nodeID, userID, ok := strings.Cut(field, ":")
+		if !ok || !live[nodeID] {
+			stale = append(stale, field)
+			continue
+		}
+		if v, _ := strconv.Atoi(n); v > 0 && !seen[userID] {
+			seen[userID] = true
+			userIDs = append(userIDs, userID)
+		}
+	}
+	if len(stale) > 0 {
+		rdb.HDel(ctx, key, stale...)
+	}
+	return userIDs, nil
+}
+
+func (h *Hub) localConnectionCount(instanceID string) int {
+	users := make(map[string]bool)
+	for _, c := range h.localClients(instanceID) {
+		users[c.UserID] = true
+	}
+	return len(users)
+}
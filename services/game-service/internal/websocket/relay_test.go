@@ -0,0 +1,187 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"game-service/config"
+	"game-service/pkg/cache"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestHub(t *testing.T, mr *miniredis.Miniredis) *Hub {
+	t.Helper()
+
+	redisClient, err := cache.NewRedisClient(&config.RedisConfig{
+		Host: mr.Host(),
+		Port: mr.Port(),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { redisClient.Close() })
+
+	hub := NewHub(nil, nil, redisClient, nil, nil, nil)
+	if err := hub.startRelay(); err != nil {
+		t.Fatalf("failed to start relay: %v", err)
+	}
+	return hub
+}
+
+func addTestClient(h *Hub, instanceID, userID string, isCreator bool) *Client {
+	c := &Client{
+		Hub:        h,
+		Send:       make(chan []byte, 16),
+		UserID:     userID,
+		InstanceID: instanceID,
+		IsCreator:  isCreator,
+	}
+
+	h.mu.Lock()
+	if h.clients[instanceID] == nil {
+		h.clients[instanceID] = make(map[*Client]bool)
+	}
+	h.clients[instanceID][c] = true
+	h.mu.Unlock()
+
+	return c
+}
+
+func expectMessage(t *testing.T, c *Client, want MessageType) Message {
+	t.Helper()
+
+	select {
+	case data := <-c.Send:
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("failed to unmarshal message for %s: %v", c.UserID, err)
+		}
+		if msg.Type != want {
+			t.Fatalf("user %s got message %q, want %q", c.UserID, msg.Type, want)
+		}
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatalf("user %s did not receive %q", c.UserID, want)
+	}
+	return Message{}
+}
+
+func expectNoMessage(t *testing.T, c *Client) {
+	t.Helper()
+
+	select {
+	case data := <-c.Send:
+		t.Fatalf("user %s got unexpected message: %s", c.UserID, data)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBroadcastReachesClientsOnOtherHub(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hubA := newTestHub(t, mr)
+	hubB := newTestHub(t, mr)
+
+	local := addTestClient(hubA, "inst-1", "user-a", false)
+	remote := addTestClient(hubB, "inst-1", "user-b", false)
+	otherInstance := addTestClient(hubB, "inst-2", "user-c", false)
+
+	hubA.broadcastToInstance("inst-1", MessageTypeQuizStarted, QuizStartedPayload{QuizType: "sync"})
+
+	expectMessage(t, local, MessageTypeQuizStarted)
+	expectMessage(t, remote, MessageTypeQuizStarted)
+	expectNoMessage(t, local)
+	expectNoMessage(t, otherInstance)
+}
+
+func TestBroadcastToParticipantsSkipsRemoteCreator(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hubA := newTestHub(t, mr)
+	hubB := newTestHub(t, mr)
+
+	creator := addTestClient(hubA, "inst-1", "creator", true)
+	participant := addTestClient(hubB, "inst-1", "user-b", false)
+
+	hubB.broadcastToParticipants("inst-1", MessageTypeTimeExpired, TimeExpiredPayload{QuestionIndex: 2})
+
+	msg := expectMessage(t, participant, MessageTypeTimeExpired)
+	payload, _ := msg.Payload.(map[string]any)
+	if payload["question_index"] != float64(2) {
+		t.Fatalf("unexpected payload: %v", msg.Payload)
+	}
+	expectNoMessage(t, creator)
+}
+
+func TestCreatorProgressReachesCreatorOnOtherHub(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hubA := newTestHub(t, mr)
+	hubB := newTestHub(t, mr)
+
+	creator := addTestClient(hubA, "inst-1", "creator", true)
+	participant := addTestClient(hubB, "inst-1", "user-b", false)
+
+	hubB.sendToCreator("inst-1", MessageTypeWaitingForCreator, WaitingForCreatorPayload{
+		QuestionIndex: 0,
+		Reason:        "Question in progress: 1/1 answered",
+	})
+
+	expectMessage(t, creator, MessageTypeWaitingForCreator)
+	expectNoMessage(t, participant)
+}
+
+func TestConnectionCountSharedAcrossHubs(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hubA := newTestHub(t, mr)
+	hubB := newTestHub(t, mr)
+	ctx := context.Background()
+
+	if got := hubA.trackConnection(ctx, "inst-1", "user-a", 1); got != 1 {
+		t.Fatalf("count after first join = %d, want 1", got)
+	}
+	if got := hubB.trackConnection(ctx, "inst-1", "user-b", 1); got != 2 {
+		t.Fatalf("count after second join = %d, want 2", got)
+	}
+	if got := hubB.trackConnection(ctx, "inst-1", "user-a", 1); got != 2 {
+		t.Fatalf("count after reconnect on other hub = %d, want 2", got)
+	}
+
+	if got := hubA.trackConnection(ctx, "inst-1", "user-a", -1); got != 2 {
+		t.Fatalf("count while user-a still holds a socket = %d, want 2", got)
+	}
+	if got := hubB.trackConnection(ctx, "inst-1", "user-a", -1); got != 1 {
+		t.Fatalf("count after user-a left = %d, want 1", got)
+	}
+
+	users := hubA.connectedUserIDs(ctx, "inst-1")
+	if len(users) != 1 || users[0] != "user-b" {
+		t.Fatalf("connected users = %v, want [user-b]", users)
+	}
+}
+
+func TestConnectionCountIgnoresDeadNode(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hubA := newTestHub(t, mr)
+	hubB := newTestHub(t, mr)
+	ctx := context.Background()
+
+	hubA.trackConnection(ctx, "inst-1", "user-a", 1)
+	hubB.trackConnection(ctx, "inst-1", "user-b", 1)
+
+	// hubB crashes without unregistering: its heartbeat goes stale.
+	stale := time.Now().Add(-2 * nodeTTL).UnixMilli()
+	if _, err := mr.ZAdd(liveNodesKey, float64(stale), hubB.nodeID); err != nil {
+		t.Fatalf("failed to age heartbeat: %v", err)
+	}
+
+	if got := hubA.connectionCount(ctx, "inst-1"); got != 1 {
+		t.Fatalf("count with dead node = %d, want 1", got)
+	}
+	if mr.HGet(connectionsKey("inst-1"), connectionField(hubB.nodeID, "user-b")) != "" {
+		t.Fatal("dead node's connection entry was not removed")
+	}
+	if got := hubA.trackConnection(ctx, "inst-1", "user-a", -1); got != 0 {
+		t.Fatalf("count after last live user left = %d, want 0", got)
+	}
+}
@@ -0,0 +1,141 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+)
+
+const resultsReadyQueue = "quiz.results_ready"
+
+type participantResult struct {
+	UserID string `json:"user_id"`
+	Score  int    `json:"score"`
+	Rank   int    `json:"rank"`
+}
+
+type resultsReadyEvent struct {
+	InstanceID     string              `json:"instance_id"`
+	Title          string              `json:"title"`
+	ParticipantIDs []string            `json:"participant_ids"`
+	Results        []participantResult `json:"results"`
+}
+
+func resultsPublishedKey(instanceID string) string {
+	return fmt.Sprintf("quiz:%s:results_published", instanceID)
+}
+
+// publishResultsReady tells notification-service that the final scores and
+// ranks of the instance are available. The results of an instance are
+// published once, whichever replica or path ends it.
+func (h *Hub) publishResultsReady(ctx context.Context, instanceID string, quizData *models.QuizData) {
+	if h.mqPublisher == nil {
+		return
+	}
+
+	event := resultsReadyEvent{
+		InstanceID:     instanceID,
+		Title:          quizData.Title,
+		ParticipantIDs: []string{},
+		Results:        []participantResult{},
+	}
+	for _, entry := range h.getLeaderboard(ctx, instanceID) {
+		event.ParticipantIDs = append(event.ParticipantIDs, entry.UserID)
+		event.Results = append(event.Results, participantResult{
+			UserID: entry.UserID,
+			Score:  entry.Score,
+			Rank:   entry.Rank,
+		})
+	}
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to marshal results_ready event: %v", err)
+		return
+	}
+
+	if h.redisClient != nil {
+		set, err := h.redisClient.GetClient().SetNX(ctx, resultsPublishedKey(instanceID), 1, 24*time.Hour).Result()
+		if err != nil {
+			log.Printf("Failed to claim results_ready event: %v", err)
+			return
+		}
+		if !set {
+			return
+		}
+	}
+
+	if err := h.mqPublisher.Publish(ctx, resultsReadyQueue, eventJSON); err != nil {
+		log.Printf("Failed to publish results_ready event: %v", err)
+		if h.redisClient != nil {
+			h.redisClient.GetClient().Del(ctx, resultsPublishedKey(instanceID))
+		}
+	}
+}
+
+// scheduleInstanceDeadline arranges for an async quiz to end at its deadline
+// and reports whether the deadline is still ahead. Scheduling it again on
+// every join is harmless: the deadline is one member of the deadline set. A
+// passed deadline is never scheduled, since it has already fired.
+func (h *Hub) scheduleInstanceDeadline(instanceID string, deadline time.Time) bool {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return false
+	}
+
+	h.scheduleQuestionDeadline(questionDeadline{
+		InstanceID: instanceID,
+		Instance:   true,
+	}, remaining)
+	return true
+}
+
+// handleInstanceDeadline ends an async quiz for everyone still taking it.
+// quiz-service owns the deadline: it closes the instance, finishes the
+// sessions of participants who are not connected and publishes the results.
+// This side only ends the quiz for connected participants; finishQuiz grades
+// the sessions it finishes itself.
+func (h *Hub) handleInstanceDeadline(instanceID string) {
+	ctx := context.Background()
+
+	log.Printf("Deadline reached: instance=%s", instanceID)
+
+	// finishQuiz needs the quiz data to request grading, and the cache may
+	// have expired before a deadline days away.
+	if _, err := h.loadQuizData(ctx, instanceID); err != nil {
+		log.Printf("Failed to get quiz data for instance deadline: %v", err)
+	}
+
+	h.broadcastToInstance(instanceID, MessageTypeQuizEnded, QuizEndedPayload{
+		Reason: constants.EndReasonDeadline,
+	})
+	h.relay(&relayEnvelope{
+		InstanceID: instanceID,
+		Kind:       relayKindFinish,
+	})
+}
+
+// loadQuizData returns the cached quiz data, reloading it from quiz-service
+// when the cache expired, as it can before a deadline days away.
+func (h *Hub) loadQuizData(ctx context.Context, instanceID string) (*models.QuizData, error) {
+	quizData, err := h.getQuizData(ctx, instanceID)
+	if err == nil || h.quizClient == nil {
+		return quizData, err
+	}
+
+	quizResp, err := h.quizClient.GetInstance(ctx, instanceID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	quizData = h.convertToQuizData(quizResp)
+	if err := h.cacheQuizData(ctx, instanceID, quizData); err != nil {
+		log.Printf("Failed to cache quiz data: %v", err)
+	}
+	return quizData, nil
+}
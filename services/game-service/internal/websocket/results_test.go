@@ -0,0 +1,133 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"game-service/internal/models"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestPublishResultsReadyIncludesScoresAndRanks(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	publisher := &recordingPublisher{}
+	hub.mqPublisher = publisher
+	ctx := context.Background()
+
+	if err := hub.cacheQuizData(ctx, "inst-1", &models.QuizData{Title: "Capitals", CreatedBy: "host"}); err != nil {
+		t.Fatal(err)
+	}
+	hub.cacheLeaderboard(ctx, "inst-1", []LeaderboardEntry{
+		{UserID: "a", Score: 10, TotalTimeMs: 3000},
+		{UserID: "b", Score: 10, TotalTimeMs: 3000},
+		{UserID: "c", Score: 4, TotalTimeMs: 1000},
+	})
+
+	hub.publishResultsReady(ctx, "inst-1", &models.QuizData{Title: "Capitals"})
+
+	if publisher.queue != resultsReadyQueue {
+		t.Fatalf("published to %q, want %q", publisher.queue, resultsReadyQueue)
+	}
+	var event resultsReadyEvent
+	if err := json.Unmarshal(publisher.body, &event); err != nil {
+		t.Fatalf("invalid event: %v", err)
+	}
+	if event.InstanceID != "inst-1" || event.Title != "Capitals" || len(event.ParticipantIDs) != 3 {
+		t.Fatalf("event = %+v", event)
+	}
+	ranks := map[string]int{}
+	for _, r := range event.Results {
+		ranks[r.UserID] = r.Rank
+	}
+	if ranks["a"] != 1 || ranks["b"] != 1 || ranks["c"] != 3 {
+		t.Fatalf("ranks = %v, want a=1 b=1 c=3", ranks)
+	}
+}
+
+func TestInstanceDeadlineScheduledOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	ctx := context.Background()
+
+	deadline := time.Now().Add(time.Hour)
+	hub.scheduleInstanceDeadline("inst-1", deadline)
+	hub.scheduleInstanceDeadline("inst-1", deadline)
+
+	count, err := hub.redisClient.GetClient().ZCard(ctx, questionDeadlinesKey).Result()
+	if err != nil || count != 1 {
+		t.Fatalf("pending deadlines = %d (err %v), want 1", count, err)
+	}
+	if got := hub.fireDueDeadlines(ctx, time.Now().Add(30*time.Minute)); got != 0 {
+		t.Fatalf("fired %d deadlines before the instance deadline, want 0", got)
+	}
+
+	hub.cancelQuestionDeadline(questionDeadline{InstanceID: "inst-1", Instance: true})
+	if count, _ := hub.redisClient.GetClient().ZCard(ctx, questionDeadlinesKey).Result(); count != 0 {
+		t.Fatalf("pending deadlines after cancel = %d, want 0", count)
+	}
+}
+
+func TestInstanceDeadlineEndsQuizOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	publisher := &recordingPublisher{}
+	hub.mqPublisher = publisher
+	ctx := context.Background()
+
+	if err := hub.cacheQuizData(ctx, "inst-1", &models.QuizData{Title: "Capitals", QuizType: "async"}); err != nil {
+		t.Fatal(err)
+	}
+	hub.cacheLeaderboard(ctx, "inst-1", []LeaderboardEntry{{UserID: "a", Score: 3}})
+	spectator := addTestClient(hub, "inst-1", "screen", false)
+	spectator.IsSpectator = true
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	if !hub.scheduleInstanceDeadline("inst-1", deadline) {
+		t.Fatal("future deadline was not scheduled")
+	}
+	if got := hub.fireDueDeadlines(ctx, deadline); got != 1 {
+		t.Fatalf("fired %d deadlines, want 1", got)
+	}
+	expectMessage(t, spectator, MessageTypeQuizEnded)
+	expectMessage(t, spectator, MessageTypeLeaderboard)
+	expectMessage(t, spectator, MessageTypeQuizFinished)
+
+	// quiz-service closes the instance and publishes its results.
+	if got := publisher.published(); got != 0 {
+		t.Fatalf("published %d events at the deadline, want 0", got)
+	}
+	time.Sleep(time.Until(deadline))
+
+	// A participant joining after the deadline must not bring it back.
+	if hub.scheduleInstanceDeadline("inst-1", deadline) {
+		t.Fatal("passed deadline was scheduled again")
+	}
+	if got := hub.fireDueDeadlines(ctx, time.Now().Add(time.Hour)); got != 0 {
+		t.Fatalf("fired %d deadlines after the re-join, want 0", got)
+	}
+	expectNoMessage(t, spectator)
+}
+
+func TestPublishResultsReadyOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	publisher := &recordingPublisher{}
+	hub.mqPublisher = publisher
+	ctx := context.Background()
+
+	quizData := &models.QuizData{Title: "Capitals"}
+	if err := hub.cacheQuizData(ctx, "inst-1", quizData); err != nil {
+		t.Fatal(err)
+	}
+	hub.cacheLeaderboard(ctx, "inst-1", []LeaderboardEntry{{UserID: "a", Score: 3}})
+
+	hub.publishResultsReady(ctx, "inst-1", quizData)
+	hub.publishResultsReady(ctx, "inst-1", quizData)
+	if got := publisher.published(); got != 1 {
+		t.Fatalf("published %d results_ready events, want 1", got)
+	}
+}
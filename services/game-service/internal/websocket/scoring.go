@@ -0,0 +1,169 @@
+package websocket
+
+import (
+	"math"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+)
+
+// Streak bonus defaults, as a share of the score per previous correct answer
+// in a row and in total.
+const (
+	defaultStreakBonus    = 0.1
+	defaultStreakBonusMax = 0.5
+)
+
+// ScoreInput is everything a strategy needs to score one answer. It is
+// recorded with the answer so scores can be recomputed later.
+type ScoreInput struct {
+	QuestionType string
+	MaxScore     int
+	Credit       float64 // share of the answer that is correct, from 0 to 1
+	TimeSpentMs  int64
+	TimeLimitMs  int64 // 0 = no limit
+	Streak       int   // correct answers in a row before this one
+}
+
+type ScoringStrategy interface {
+	Name() string
+	Score(in ScoreInput) int
+}
+
+// scoringStrategy picks the strategy of the settings. A streak bonus set on
+// another strategy applies on top of it.
+func scoringStrategy(settings models.Settings) ScoringStrategy {
+	var base ScoringStrategy = kahootScoring{}
+	switch settings.ScoringStrategy {
+	case constants.ScoringFlat:
+		base = flatScoring{}
+	case constants.ScoringLinear:
+		base = linearScoring{floor: float64(settings.ScoringFloorPercent) / 100}
+	case constants.ScoringStreak:
+		return newStreakScoring(settings, base, constants.ScoringStreak)
+	case constants.ScoringNegative:
+		base = negativeScoring{penalty: float64(settings.ScoringPenaltyPercent) / 100}
+	}
+	if settings.StreakBonusPercent > 0 {
+		return newStreakScoring(settings, base, base.Name())
+	}
+	return base
+}
+
+func timeRatio(in ScoreInput) float64 {
+	if in.TimeLimitMs <= 0 {
+		return 0
+	}
+	return min(float64(in.TimeSpentMs)/float64(in.TimeLimitMs), 1)
+}
+
+// flatScoring ignores answer time.
+type flatScoring struct{}
+
+func (flatScoring) Name() string { return constants.ScoringFlat }
+
+func (flatScoring) Score(in ScoreInput) int {
+	return int(float64(in.MaxScore) * in.Credit)
+}
+
+// linearScoring decays from max_score to floor*max_score at the time limit.
+type linearScoring struct {
+	floor float64
+}
+
+func (linearScoring) Name() string { return constants.ScoringLinear }
+
+func (s linearScoring) Score(in ScoreInput) int {
+	factor := 1 - (1-s.floor)*timeRatio(in)
+	return int(float64(in.MaxScore) * factor * in.Credit)
+}
+
+// kahootScoring loses up to half of max_score over the time limit. It is the
+// default and matches how scores were computed before strategies existed.
+type kahootScoring struct{}
+
+func (kahootScoring) Name() string { return constants.ScoringKahoot }
+
+func (kahootScoring) Score(in ScoreInput) int {
+	return int(float64(in.MaxScore) * (1 - 0.5*timeRatio(in)) * in.Credit)
+}
+
+// streakScoring adds a bonus for every previous correct answer in a row to
+// the score of its base strategy.
+type streakScoring struct {
+	name     string
+	base     ScoringStrategy
+	step     float64
+	maxBonus float64
+}
+
+func newStreakScoring(settings models.Settings, base ScoringStrategy, name string) streakScoring {
+	s := streakScoring{
+		name:     name,
+		base:     base,
+		step:     defaultStreakBonus,
+		maxBonus: defaultStreakBonusMax,
+	}
+	if settings.StreakBonusPercent > 0 {
+		s.step = float64(settings.StreakBonusPercent) / 100
+	}
+	if settings.StreakBonusMaxPercent > 0 {
+		s.maxBonus = float64(settings.StreakBonusMaxPercent) / 100
+	}
+	return s
+}
+
+func (s streakScoring) Name() string { return s.name }
+
+func (s streakScoring) Score(in ScoreInput) int {
+	score := s.base.Score(in)
+	if score <= 0 {
+		return score
+	}
+	return int(math.Round(float64(score) * s.Multiplier(in.Streak)))
+}
+
+func (s streakScoring) Multiplier(streak int) float64 {
+	return 1 + min(float64(streak)*s.step, s.maxBonus)
+}
+
+// streakMultiplier is the multiplier the strategy applies for a streak, 1 for
+// strategies without a streak bonus.
+func streakMultiplier(strategy ScoringStrategy, streak int) float64 {
+	if s, ok := strategy.(streakScoring); ok {
+		return s.Multiplier(streak)
+	}
+	return 1
+}
+
+// negativeScoring gives max_score for a correct answer and takes penalty of
+// max_score away for a wrong choice. Guessing nothing right on a choice
+// question is what counts as wrong; other question types never go negative.
+type negativeScoring struct {
+	penalty float64
+}
+
+func (negativeScoring) Name() string { return constants.ScoringNegative }
+
+func (s negativeScoring) Score(in ScoreInput) int {
+	if in.Credit > 0 {
+		return int(float64(in.MaxScore) * in.Credit)
+	}
+	switch in.QuestionType {
+	case constants.QuestionTypeMultipleChoice, constants.QuestionTypeMultiSelect, constants.QuestionTypeTrueFalse:
+		return -int(math.Round(float64(in.MaxScore) * s.penalty))
+	}
+	return 0
+}
+
+// nextStreak returns the streak after an answer. Answers waiting for manual
+// review neither extend nor break it.
+func nextStreak(streak int, isCorrect, needsReview bool) int {
+	switch {
+	case needsReview:
+		return streak
+	case isCorrect:
+		return streak + 1
+	}
+	return 0
+}
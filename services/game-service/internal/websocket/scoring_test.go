@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"testing"
+
+	"game-service/internal/models"
+)
+
+func TestScoringStrategies(t *testing.T) {
+	half := ScoreInput{QuestionType: "multiple_choice", MaxScore: 100, Credit: 1, TimeSpentMs: 5000, TimeLimitMs: 10000}
+	wrong := half
+	wrong.Credit = 0
+
+	tests := []struct {
+		settings models.Settings
+		in       ScoreInput
+		want     int
+	}{
+		{models.Settings{}, half, 75},
+		{models.Settings{ScoringStrategy: "kahoot"}, ScoreInput{MaxScore: 100, Credit: 1}, 100},
+		{models.Settings{ScoringStrategy: "flat"}, half, 100},
+		{models.Settings{ScoringStrategy: "linear", ScoringFloorPercent: 20}, half, 60},
+		{models.Settings{ScoringStrategy: "linear"}, ScoreInput{MaxScore: 100, Credit: 1, TimeSpentMs: 20000, TimeLimitMs: 10000}, 0},
+		{models.Settings{ScoringStrategy: "streak"}, ScoreInput{MaxScore: 100, Credit: 1, Streak: 3}, 130},
+		{models.Settings{ScoringStrategy: "streak"}, ScoreInput{MaxScore: 100, Credit: 1, Streak: 9}, 150},
+		{models.Settings{ScoringStrategy: "flat", StreakBonusPercent: 20}, ScoreInput{MaxScore: 100, Credit: 1, Streak: 2}, 140},
+		{models.Settings{ScoringStrategy: "negative", ScoringPenaltyPercent: 25}, wrong, -25},
+		{models.Settings{ScoringStrategy: "negative", ScoringPenaltyPercent: 25, StreakBonusPercent: 20}, ScoreInput{QuestionType: "multiple_choice", MaxScore: 100, Streak: 2}, -25},
+		{models.Settings{ScoringStrategy: "negative", ScoringPenaltyPercent: 25}, ScoreInput{QuestionType: "numeric", MaxScore: 100}, 0},
+		{models.Settings{ScoringStrategy: "negative", ScoringPenaltyPercent: 25}, ScoreInput{QuestionType: "multi_select", MaxScore: 100, Credit: 0.5}, 50},
+	}
+
+	for _, tt := range tests {
+		strategy := scoringStrategy(tt.settings)
+		if got := strategy.Score(tt.in); got != tt.want {
+			t.Errorf("%s: Score(%+v) = %d, want %d", strategy.Name(), tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStreakMultiplierSettings(t *testing.T) {
+	strategy := scoringStrategy(models.Settings{ScoringStrategy: "streak", StreakBonusPercent: 25, StreakBonusMaxPercent: 100})
+	if got := streakMultiplier(strategy, 2); got != 1.5 {
+		t.Fatalf("multiplier for streak 2 = %v, want 1.5", got)
+	}
+	if got := streakMultiplier(strategy, 10); got != 2 {
+		t.Fatalf("multiplier for streak 10 = %v, want capped 2", got)
+	}
+	if got := streakMultiplier(scoringStrategy(models.Settings{}), 10); got != 1 {
+		t.Fatalf("multiplier without streak strategy = %v, want 1", got)
+	}
+
+	linear := scoringStrategy(models.Settings{ScoringStrategy: "linear", StreakBonusPercent: 25})
+	if got := streakMultiplier(linear, 2); got != 1.5 {
+		t.Fatalf("multiplier for streak 2 on linear = %v, want 1.5", got)
+	}
+	if linear.Name() != "linear" {
+		t.Fatalf("strategy name = %q, want linear", linear.Name())
+	}
+}
+
+func TestNextStreak(t *testing.T) {
+	streak := 0
+	for _, a := range []struct{ correct, review bool }{{true, false}, {true, false}, {false, true}, {true, false}} {
+		streak = nextStreak(streak, a.correct, a.review)
+	}
+	if streak != 3 {
+		t.Fatalf("streak = %d, want 3", streak)
+	}
+	if got := nextStreak(streak, false, false); got != 0 {
+		t.Fatalf("streak after a wrong answer = %d, want 0", got)
+	}
+}
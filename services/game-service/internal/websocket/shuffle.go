@@ -0,0 +1,267 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+)
+
+func shuffleEnabled(quizData *models.QuizData) bool {
+	return quizData.QuizType == constants.QuizTypeAsync &&
+		(quizData.Settings.RandomOrder || quizData.Settings.ShuffleOptions)
+}
+
+// newShuffleOrder builds the participant's permutation. It is seeded from the
+// instance and user, so the same participant always gets the same order.
+func newShuffleOrder(quizData *models.QuizData, instanceID, userID string) *models.ShuffleOrder {
+	if !shuffleEnabled(quizData) {
+		return nil
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(instanceID + ":" + userID))
+	seed := h.Sum64()
+	rng := rand.New(rand.NewPCG(seed, seed>>1|1))
+
+	order := &models.ShuffleOrder{
+		QuestionIDs: make([]string, len(quizData.Questions)),
+	}
+	for i, q := range quizData.Questions {
+		order.QuestionIDs[i] = q.ID
+	}
+	if quizData.Settings.RandomOrder {
+		rng.Shuffle(len(order.QuestionIDs), func(i, j int) {
+			order.QuestionIDs[i], order.QuestionIDs[j] = order.QuestionIDs[j], order.QuestionIDs[i]
+		})
+	}
+
+	if quizData.Settings.ShuffleOptions {
+		order.Options = make(map[string][]int)
+		for _, q := range quizData.Questions {
+			if !hasShuffledOptions(q.Type) || len(q.Options) < 2 {
+				continue
+			}
+			order.Options[q.ID] = rng.Perm(len(q.Options))
+		}
+	}
+
+	return order
+}
+
+// applyShuffleOrder returns a copy of the quiz as the participant sees it.
+// Questions missing from the order are appended in their original order.
+func applyShuffleOrder(quizData *models.QuizData, order *models.ShuffleOrder) *models.QuizData {
+	if order == nil {
+		return quizData
+	}
+
+	byID := make(map[string]models.Question, len(quizData.Questions))
+	for _, q := range quizData.Questions {
+		byID[q.ID] = q
+	}
+
+	arranged := *quizData
+	arranged.Questions = make([]models.Question, 0, len(quizData.Questions))
+	for _, id := range order.QuestionIDs {
+		if q, ok := byID[id]; ok {
+			arranged.Questions = append(arranged.Questions, q)
+			delete(byID, id)
+		}
+	}
+	for _, q := range quizData.Questions {
+		if _, ok := byID[q.ID]; ok {
+			arranged.Questions = append(arranged.Questions, q)
+		}
+	}
+
+	for i, q := range arranged.Questions {
+		perm, ok := order.Options[q.ID]
+		if !ok || len(perm) != len(q.Options) {
+			continue
+		}
+		options := make([]string, len(perm))
+		for pos, idx := range perm {
+			options[pos] = q.Options[idx]
+		}
+		arranged.Questions[i].Options = options
+	}
+
+	return &arranged
+}
+
+// participantQuizData returns the quiz in the order the participant sees it,
+// along with the order so answers can be mapped back.
+func (h *Hub) participantQuizData(ctx context.Context, quizData *models.QuizData, instanceID, userID string) (*models.QuizData, *models.ShuffleOrder) {
+	if !shuffleEnabled(quizData) {
+		return quizData, nil
+	}
+
+	session, err := h.sessionRepo.GetSession(ctx, instanceID, userID)
+	if err != nil {
+		log.Printf("Failed to get session for question order: %v", err)
+		return quizData, nil
+	}
+
+	order := sessionShuffleOrder(session, quizData)
+	return applyShuffleOrder(quizData, order), order
+}
+
+func sessionShuffleOrder(session *models.GameSession, quizData *models.QuizData) *models.ShuffleOrder {
+	if session.QuestionOrder != "" {
+		var order models.ShuffleOrder
+		if err := json.Unmarshal([]byte(session.QuestionOrder), &order); err == nil {
+			return &order
+		}
+		log.Printf("Failed to parse question order for user %s, regenerating", session.UserID)
+	}
+	return newShuffleOrder(quizData, session.InstanceID, session.UserID)
+}
+
+func hasShuffledOptions(questionType string) bool {
+	switch questionType {
+	case constants.QuestionTypeMultipleChoice, constants.QuestionTypeMultiSelect,
+		constants.QuestionTypeOrdering, constants.QuestionTypeMatching:
+		return true
+	}
+	return false
+}
+
+// decodeCorrectAnswer returns a JSON string answer as plain text and any
+// other answer as compact JSON.
+func decodeCorrectAnswer(correctAnswerJSON string) string {
+	var correct any
+	if err := json.Unmarshal([]byte(correctAnswerJSON), &correct); err != nil {
+		return correctAnswerJSON
+	}
+	if text, ok := correct.(string); ok {
+		return text
+	}
+	data, err := json.Marshal(correct)
+	if err != nil {
+		return correctAnswerJSON
+	}
+	return string(data)
+}
+
+// displayedAnswer returns the correct answer as the participant sees it:
+// option indices are mapped to their positions in the shuffled option list.
+func displayedAnswer(question *models.Question, order *models.ShuffleOrder) string {
+	correct := decodeCorrectAnswer(question.CorrectAnswer)
+	if order == nil {
+		return correct
+	}
+	perm, ok := order.Options[question.ID]
+	if !ok {
+		return correct
+	}
+
+	position := make(map[int]int, len(perm))
+	for pos, canonical := range perm {
+		position[canonical] = pos
+	}
+
+	switch question.Type {
+	case constants.QuestionTypeMultiSelect, constants.QuestionTypeOrdering:
+		var indices []int
+		if err := json.Unmarshal([]byte(question.CorrectAnswer), &indices); err != nil {
+			return correct
+		}
+		displayed := make([]int, len(indices))
+		for i, idx := range indices {
+			displayed[i] = position[idx]
+		}
+		if question.Type == constants.QuestionTypeMultiSelect {
+			slices.Sort(displayed)
+		}
+		return marshalAnswer(displayed, correct)
+
+	case constants.QuestionTypeMatching:
+		var items []string
+		if err := json.Unmarshal([]byte(question.CorrectAnswer), &items); err != nil || len(items) != len(perm) {
+			return correct
+		}
+		displayed := make([]string, len(perm))
+		for pos, idx := range perm {
+			displayed[pos] = items[idx]
+		}
+		return marshalAnswer(displayed, correct)
+	}
+
+	idx, err := strconv.Atoi(strings.TrimSpace(correct))
+	if err != nil {
+		return correct
+	}
+	if pos, ok := position[idx]; ok {
+		return strconv.Itoa(pos)
+	}
+	return correct
+}
+
+// canonicalAnswer maps an answer given against a shuffled option list back to
+// the question's own option order. A multiple choice answer may be stored
+// either as the option index or as the option text.
+func canonicalAnswer(answer string, question *models.Question, order *models.ShuffleOrder) string {
+	if order == nil {
+		return answer
+	}
+
+	perm, ok := order.Options[question.ID]
+	if !ok {
+		return answer
+	}
+
+	switch question.Type {
+	case constants.QuestionTypeMultiSelect, constants.QuestionTypeOrdering:
+		positions, ok := parseIndexList(answer)
+		if !ok {
+			return answer
+		}
+		indices := make([]int, len(positions))
+		for i, pos := range positions {
+			if pos < 0 || pos >= len(perm) {
+				return answer
+			}
+			indices[i] = perm[pos]
+		}
+		return marshalAnswer(indices, answer)
+
+	case constants.QuestionTypeMatching:
+		var chosen []string
+		if err := json.Unmarshal([]byte(answer), &chosen); err != nil || len(chosen) != len(perm) {
+			return answer
+		}
+		items := make([]string, len(perm))
+		for pos, idx := range perm {
+			items[idx] = chosen[pos]
+		}
+		return marshalAnswer(items, answer)
+	}
+
+	pos, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || pos < 0 || pos >= len(perm) || perm[pos] >= len(question.Options) {
+		return answer
+	}
+	idx := perm[pos]
+
+	correct := decodeCorrectAnswer(question.CorrectAnswer)
+	if _, err := strconv.Atoi(strings.TrimSpace(correct)); err == nil {
+		return strconv.Itoa(idx)
+	}
+	return question.Options[idx]
+}
+
+func marshalAnswer(answer any, fallback string) string {
+	data, err := json.Marshal(answer)
+	if err != nil {
+		return fallback
+	}
+	return string(data)
+}
@@ -0,0 +1,106 @@
+package websocket
+
+import (
+	"reflect"
+	"testing"
+
+	"game-service/internal/models"
+)
+
+func shuffleTestQuiz() *models.QuizData {
+	return &models.QuizData{
+		QuizType: "async",
+		Questions: []models.Question{
+			{ID: "q1", Type: "multiple_choice", Options: []string{"a", "b", "c", "d"}, CorrectAnswer: `"c"`},
+			{ID: "q2", Type: "multiple_choice", Options: []string{"w", "x", "y", "z"}, CorrectAnswer: `"1"`},
+			{ID: "q3", Type: "open", CorrectAnswer: `"paris"`},
+			{ID: "q4", Type: "open", CorrectAnswer: `"rome"`},
+		},
+		Settings: models.Settings{RandomOrder: true, ShuffleOptions: true},
+	}
+}
+
+func TestShuffleOrderIsDeterministicPerUser(t *testing.T) {
+	quizData := shuffleTestQuiz()
+
+	first := newShuffleOrder(quizData, "inst-1", "user-a")
+	again := newShuffleOrder(quizData, "inst-1", "user-a")
+	if !reflect.DeepEqual(first, again) {
+		t.Fatalf("order changed between calls: %v vs %v", first, again)
+	}
+
+	arranged := applyShuffleOrder(quizData, first)
+	if len(arranged.Questions) != len(quizData.Questions) {
+		t.Fatalf("arranged %d questions, want %d", len(arranged.Questions), len(quizData.Questions))
+	}
+	seen := make(map[string]bool)
+	for _, q := range arranged.Questions {
+		seen[q.ID] = true
+	}
+	if len(seen) != len(quizData.Questions) {
+		t.Fatalf("arranged questions are not a permutation: %v", first.QuestionIDs)
+	}
+	if quizData.Questions[0].Options[0] != "a" {
+		t.Fatal("applyShuffleOrder modified the canonical quiz")
+	}
+}
+
+func TestShuffleDisabledForSyncQuizzes(t *testing.T) {
+	quizData := shuffleTestQuiz()
+	quizData.QuizType = "sync"
+
+	if order := newShuffleOrder(quizData, "inst-1", "user-a"); order != nil {
+		t.Fatalf("sync quiz got a shuffle order: %v", order)
+	}
+}
+
+func TestCanonicalAnswerMapsShuffledOptions(t *testing.T) {
+	quizData := shuffleTestQuiz()
+	order := &models.ShuffleOrder{
+		QuestionIDs: []string{"q1", "q2", "q3", "q4"},
+		Options: map[string][]int{
+			"q1": {2, 0, 3, 1},
+			"q2": {3, 1, 0, 2},
+		},
+	}
+	arranged := applyShuffleOrder(quizData, order)
+
+	if got := arranged.Questions[0].Options; !reflect.DeepEqual(got, []string{"c", "a", "d", "b"}) {
+		t.Fatalf("shuffled options = %v", got)
+	}
+
+	tests := []struct {
+		question *models.Question
+		answer   string
+		want     string
+	}{
+		{&quizData.Questions[0], "0", "c"},
+		{&quizData.Questions[0], "3", "b"},
+		{&quizData.Questions[1], "1", "1"},
+		{&quizData.Questions[1], "0", "3"},
+		{&quizData.Questions[0], "c", "c"},
+		{&quizData.Questions[2], "paris", "paris"},
+	}
+	for _, tt := range tests {
+		if got := canonicalAnswer(tt.answer, tt.question, order); got != tt.want {
+			t.Errorf("canonicalAnswer(%q) for %s = %q, want %q", tt.answer, tt.question.ID, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayedAnswerFollowsShuffledOptions(t *testing.T) {
+	quizData := shuffleTestQuiz()
+	order := &models.ShuffleOrder{
+		Options: map[string][]int{"q2": {3, 1, 0, 2}},
+	}
+
+	if got := displayedAnswer(&quizData.Questions[1], order); got != "1" {
+		t.Fatalf("displayed index answer = %q, want %q", got, "1")
+	}
+	if got := displayedAnswer(&quizData.Questions[0], order); got != "c" {
+		t.Fatalf("displayed text answer = %q, want %q", got, "c")
+	}
+	if got := displayedAnswer(&quizData.Questions[1], nil); got != "1" {
+		t.Fatalf("unshuffled answer = %q, want %q", got, "1")
+	}
+}
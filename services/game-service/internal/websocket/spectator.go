@@ -0,0 +1,203 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+)
+
+// handleSpectatorJoin brings a spectator screen up to date: the current
+// question with its remaining time, the answers so far and the leaderboard.
+// Later updates arrive through the relay like for any other socket.
+func (h *Hub) handleSpectatorJoin(client *Client) {
+	ctx := context.Background()
+
+	quizResp, err := h.quizClient.GetInstance(ctx, client.InstanceID, client.UserID)
+	if err != nil {
+		log.Printf("Failed to get quiz instance: %v", err)
+		client.SendError("Failed to load quiz")
+		return
+	}
+
+	quizData := h.convertToQuizData(quizResp)
+	if quizData.QuizType != constants.QuizTypeSync {
+		client.SendError("Only sync quizzes can be watched")
+
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			h.Unregister <- client
+		}()
+		return
+	}
+
+	if err := h.cacheQuizData(ctx, client.InstanceID, quizData); err != nil {
+		log.Printf("Failed to cache quiz data: %v", err)
+	}
+
+	client.SendMessage(MessageTypeConnected, ConnectedPayload{
+		QuizType:    quizData.QuizType,
+		QuizStatus:  quizResp.Instance.Status,
+		IsSpectator: true,
+	})
+
+	if isInstanceClosed(quizResp.Instance.Status) {
+		h.finishSpectator(client)
+		return
+	}
+	if quizResp.Instance.Status != constants.InstanceStatusActive {
+		return
+	}
+
+	questionIndex := h.currentQuestionIndex(ctx, client.InstanceID)
+	h.sendLeaderboardToClient(client, questionIndex)
+
+	if questionIndex >= len(quizData.Questions) || h.questionStartTime(ctx, client.InstanceID, questionIndex) == 0 {
+		return
+	}
+
+	startTimeKey := fmt.Sprintf("quiz:%s:question:%d:start", client.InstanceID, questionIndex)
+	payload, _ := h.prepareQuestion(ctx, startTimeKey, quizData, questionIndex)
+	setTotalRemaining(&payload, h.quizEndsAt(ctx, client.InstanceID, "", quizData))
+	client.SendMessage(MessageTypeQuestion, payload)
+
+	h.sendAnswerHistogram(client, quizData, questionIndex)
+
+	if state, paused := h.getPauseState(ctx, client.InstanceID); paused {
+		client.SendMessage(MessageTypeQuizPaused, QuizPausedPayload{
+			QuestionIndex: state.QuestionIndex,
+			RemainingMs:   state.RemainingMs,
+		})
+	}
+}
+
+// finishSpectator shows the final standings on a spectator screen.
+func (h *Hub) finishSpectator(client *Client) {
+	h.sendLeaderboardToClient(client, 0)
+	client.SendMessage(MessageTypeQuizFinished, QuizFinishedPayload{})
+}
+
+func (h *Hub) broadcastAnswerHistogram(ctx context.Context, instanceID string, quizData *models.QuizData, questionIndex int) {
+	histogram, err := h.answerHistogram(ctx, instanceID, quizData, questionIndex)
+	if err != nil {
+		log.Printf("Failed to build answer histogram for instance %s: %v", instanceID, err)
+		return
+	}
+	h.sendToCreator(instanceID, MessageTypeAnswerHistogram, histogram)
+	h.broadcastToSpectators(instanceID, MessageTypeAnswerHistogram, histogram)
+}
+
+func (h *Hub) answerHistogram(ctx context.Context, instanceID string, quizData *models.QuizData, questionIndex int) (AnswerHistogramPayload, error) {
+	question := &quizData.Questions[questionIndex]
+
+	sessions, err := h.sessionRepo.GetSessionsByInstance(ctx, instanceID)
+	if err != nil {
+		return AnswerHistogramPayload{}, err
+	}
+
+	participants := 0
+	var answers []models.Answer
+	for _, session := range sessions {
+		if session.UserID == quizData.CreatedBy {
+			continue
+		}
+		if session.Status != constants.SessionStatusInProgress && session.Status != constants.SessionStatusFinished {
+			continue
+		}
+
+		participants++
+		if answer, ok := findAnswer(session, question.ID); ok {
+			answers = append(answers, answer)
+		}
+	}
+
+	histogram := summarizeAnswers(question, answers)
+	histogram.QuestionIndex = questionIndex
+	histogram.Participants = participants
+	return histogram, nil
+}
+
+func findAnswer(session *models.GameSession, questionID string) (models.Answer, bool) {
+	var answers []models.Answer
+	if err := json.Unmarshal([]byte(session.Answers), &answers); err != nil {
+		return models.Answer{}, false
+	}
+	for _, a := range answers {
+		if a.QuestionID == questionID {
+			return a, true
+		}
+	}
+	return models.Answer{}, false
+}
+
+// histogramBuckets counts the canonical answers per option. Answers that do
+// not name a valid option are left out.
+func histogramBuckets(question *models.Question, answers []string) []HistogramBucket {
+	switch question.Type {
+	case constants.QuestionTypeMultipleChoice, constants.QuestionTypeMultiSelect:
+		buckets := make([]HistogramBucket, len(question.Options))
+		for i, option := range question.Options {
+			buckets[i].Label = option
+		}
+		for _, answer := range answers {
+			for _, idx := range chosenOptions(question, answer) {
+				buckets[idx].Count++
+			}
+		}
+		return buckets
+
+	case constants.QuestionTypeTrueFalse:
+		buckets := []HistogramBucket{{Label: "true"}, {Label: "false"}}
+		for _, answer := range answers {
+			value, err := strconv.ParseBool(normalizeText(answer))
+			if err != nil {
+				continue
+			}
+			if value {
+				buckets[0].Count++
+			} else {
+				buckets[1].Count++
+			}
+		}
+		return buckets
+	}
+	return nil
+}
+
+// chosenOptions returns the option indices a choice answer picks. A
+// multiple_choice answer may be an index or the option text.
+func chosenOptions(question *models.Question, answer string) []int {
+	if question.Type == constants.QuestionTypeMultiSelect {
+		picked, ok := parseIndexList(answer)
+		if !ok {
+			return nil
+		}
+		var indices []int
+		seen := make(map[int]bool, len(picked))
+		for _, idx := range picked {
+			if idx >= 0 && idx < len(question.Options) && !seen[idx] {
+				seen[idx] = true
+				indices = append(indices, idx)
+			}
+		}
+		return indices
+	}
+
+	if idx, err := strconv.Atoi(normalizeText(answer)); err == nil {
+		if idx >= 0 && idx < len(question.Options) {
+			return []int{idx}
+		}
+		return nil
+	}
+	for i, option := range question.Options {
+		if normalizeText(option) == normalizeText(answer) {
+			return []int{i}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package websocket
+
+import (
+	"reflect"
+	"testing"
+
+	"game-service/internal/models"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func bucketCounts(buckets []HistogramBucket) []int {
+	counts := make([]int, len(buckets))
+	for i, b := range buckets {
+		counts[i] = b.Count
+	}
+	return counts
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	tests := []struct {
+		name     string
+		question models.Question
+		answers  []string
+		want     []int
+	}{
+		{
+			name:     "multiple choice by index or text",
+			question: models.Question{Type: "multiple_choice", Options: []string{"Red", "Green", "Blue"}},
+			answers:  []string{"0", "2", " green ", "2", "7", "purple"},
+			want:     []int{1, 1, 2},
+		},
+		{
+			name:     "multi select counts each option once",
+			question: models.Question{Type: "multi_select", Options: []string{"A", "B", "C"}},
+			answers:  []string{"[0,2]", "0,0,1", "[5]", "x"},
+			want:     []int{2, 1, 1},
+		},
+		{
+			name:     "true false",
+			question: models.Question{Type: "true_false"},
+			answers:  []string{"true", "False", "TRUE", "maybe"},
+			want:     []int{2, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bucketCounts(histogramBuckets(&tt.question, tt.answers))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("counts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if buckets := histogramBuckets(&models.Question{Type: "open"}, []string{"text"}); buckets != nil {
+		t.Fatalf("open question got buckets: %v", buckets)
+	}
+}
+
+func TestSpectatorsOnlyGetSpectatorAndInstanceMessages(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hubA := newTestHub(t, mr)
+	hubB := newTestHub(t, mr)
+
+	participant := addTestClient(hubA, "inst-1", "user-a", false)
+	spectator := addTestClient(hubB, "inst-1", "screen", false)
+	spectator.IsSpectator = true
+
+	hubA.broadcastToParticipants("inst-1", MessageTypeWaitingForCreator, WaitingForCreatorPayload{QuestionIndex: 1})
+	expectMessage(t, participant, MessageTypeWaitingForCreator)
+	expectNoMessage(t, spectator)
+
+	hubA.broadcastToSpectators("inst-1", MessageTypeAnswerHistogram, AnswerHistogramPayload{QuestionIndex: 1})
+	expectMessage(t, spectator, MessageTypeAnswerHistogram)
+	expectNoMessage(t, participant)
+
+	hubA.broadcastToInstance("inst-1", MessageTypeLeaderboard, LeaderboardPayload{})
+	expectMessage(t, participant, MessageTypeLeaderboard)
+	expectMessage(t, spectator, MessageTypeLeaderboard)
+
+	if clients := hubB.localClients("inst-1"); len(clients) != 0 {
+		t.Fatalf("spectator listed as a client: %v", clients)
+	}
+}
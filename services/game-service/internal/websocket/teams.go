@@ -0,0 +1,283 @@
+package websocket
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+)
+
+func teamID(n int) string {
+	return fmt.Sprintf("team-%d", n)
+}
+
+func (h *Hub) handleAssignTeams(client *Client, payload any) {
+	ctx := context.Background()
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		client.SendError("Invalid teams format")
+		return
+	}
+
+	var teamsPayload AssignTeamsPayload
+	if err := json.Unmarshal(payloadBytes, &teamsPayload); err != nil {
+		client.SendError("Invalid teams format")
+		return
+	}
+
+	quizData, err := h.getQuizData(ctx, client.InstanceID)
+	if err != nil {
+		log.Printf("Failed to get quiz data: %v", err)
+		client.SendError("Failed to assign teams")
+		return
+	}
+
+	if quizData.QuizType != constants.QuizTypeSync {
+		client.SendError("Teams are only available in sync quizzes")
+		return
+	}
+
+	quizResp, err := h.quizClient.GetInstance(ctx, client.InstanceID, client.UserID)
+	if err != nil {
+		log.Printf("Failed to get quiz instance: %v", err)
+		client.SendError("Failed to assign teams")
+		return
+	}
+	if quizResp.Instance.Status != constants.InstanceStatusWaiting {
+		client.SendError("Teams can only be assigned before the quiz starts")
+		return
+	}
+
+	participants, err := h.teamParticipants(ctx, client.InstanceID, quizData.CreatedBy)
+	if err != nil {
+		log.Printf("Failed to get sessions for teams: %v", err)
+		client.SendError("Failed to assign teams")
+		return
+	}
+
+	var teams []models.Team
+	switch teamsPayload.Mode {
+	case constants.TeamModeManual:
+		teams, err = manualTeams(teamsPayload.Teams, participants)
+	case constants.TeamModeRandom:
+		teams, err = randomTeams(participants, teamsPayload.TeamCount)
+	case constants.TeamModeGroups:
+		teams, err = h.groupTeams(ctx, teamsPayload.GroupIDs, participants, client.UserID)
+	default:
+		err = fmt.Errorf("unknown mode %q", teamsPayload.Mode)
+	}
+	if err != nil {
+		client.SendError("Invalid teams: " + err.Error())
+		return
+	}
+
+	if err := h.teamRepo.ReplaceTeams(ctx, client.InstanceID, teams); err != nil {
+		log.Printf("Failed to save teams for instance %s: %v", client.InstanceID, err)
+		client.SendError("Failed to assign teams")
+		return
+	}
+	h.invalidateLeaderboard(ctx, client.InstanceID)
+
+	log.Printf("Assigned %d teams in instance %s (%s)", len(teams), client.InstanceID, teamsPayload.Mode)
+
+	result := TeamsAssignedPayload{Teams: make([]TeamPayload, len(teams))}
+	for i, team := range teams {
+		result.Teams[i] = TeamPayload{
+			TeamID:  team.ID,
+			Name:    team.Name,
+			UserIDs: team.UserIDs,
+		}
+	}
+	h.broadcastToInstance(client.InstanceID, MessageTypeTeamsAssigned, result)
+}
+
+// teamParticipants returns the ids of everyone who joined, except the host
+// and kicked participants.
+func (h *Hub) teamParticipants(ctx context.Context, instanceID, createdBy string) ([]string, error) {
+	sessions, err := h.sessionRepo.GetSessionsByInstance(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var participants []string
+	for _, session := range sessions {
+		if session.UserID == createdBy || session.Status == constants.SessionStatusKicked {
+			continue
+		}
+		participants = append(participants, session.UserID)
+	}
+	return participants, nil
+}
+
+func manualTeams(inputs []TeamInput, participants []string) ([]models.Team, error) {
+	if len(inputs) < 2 {
+		return nil, fmt.Errorf("at least 2 teams are needed")
+	}
+
+	assigned := make(map[string]bool, len(participants))
+	names := make(map[string]bool, len(inputs))
+	teams := make([]models.Team, 0, len(inputs))
+	for i, input := range inputs {
+		name := strings.TrimSpace(input.Name)
+		if name == "" {
+			name = fmt.Sprintf("Team %d", i+1)
+		}
+		if names[strings.ToLower(name)] {
+			return nil, fmt.Errorf("team name %q is used twice", name)
+		}
+		names[strings.ToLower(name)] = true
+
+		team := models.Team{ID: teamID(i + 1), Name: name}
+		for _, userID := range input.UserIDs {
+			if !slices.Contains(participants, userID) {
+				return nil, fmt.Errorf("user %s has not joined the quiz", userID)
+			}
+			if assigned[userID] {
+				return nil, fmt.Errorf("user %s is in more than one team", userID)
+			}
+			assigned[userID] = true
+			team.UserIDs = append(team.UserIDs, userID)
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
+
+// randomTeams deals the participants into count teams whose sizes differ by
+// at most one.
+func randomTeams(participants []string, count int) ([]models.Team, error) {
+	if len(participants) < 2 {
+		return nil, fmt.Errorf("at least 2 participants are needed")
+	}
+	if count < 2 || count > len(participants) {
+		return nil, fmt.Errorf("team count must be between 2 and %d", len(participants))
+	}
+
+	shuffled := slices.Clone(participants)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	teams := make([]models.Team, count)
+	for i := range teams {
+		teams[i] = models.Team{ID: teamID(i + 1), Name: fmt.Sprintf("Team %d", i+1)}
+	}
+	for i, userID := range shuffled {
+		teams[i%count].UserIDs = append(teams[i%count].UserIDs, userID)
+	}
+	return teams, nil
+}
+
+// groupTeams makes a team of the participants in each user-service group.
+// Participants in several groups join the first of them listed; participants
+// in none stay without a team.
+func (h *Hub) groupTeams(ctx context.Context, groupIDs []string, participants []string, hostID string) ([]models.Team, error) {
+	if len(groupIDs) < 2 {
+		return nil, fmt.Errorf("at least 2 groups are needed")
+	}
+	if h.userLookup == nil {
+		return nil, fmt.Errorf("groups are unavailable")
+	}
+
+	assigned := make(map[string]bool, len(participants))
+	teams := make([]models.Team, 0, len(groupIDs))
+	for i, groupID := range groupIDs {
+		name, members, err := h.userLookup.GetGroupMembers(ctx, groupID, hostID)
+		if err != nil {
+			log.Printf("Failed to get members of group %s: %v", groupID, err)
+			return nil, fmt.Errorf("group %s is not available", groupID)
+		}
+
+		team := models.Team{ID: teamID(i + 1), Name: name}
+		for _, userID := range members {
+			if slices.Contains(participants, userID) && !assigned[userID] {
+				assigned[userID] = true
+				team.UserIDs = append(team.UserIDs, userID)
+			}
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
+
+// getTeamLeaderboard returns the ranked teams of a sync quiz, or nothing if
+// the host did not split participants into teams.
+func (h *Hub) getTeamLeaderboard(ctx context.Context, instanceID string) []models.TeamScore {
+	quizData, err := h.getQuizData(ctx, instanceID)
+	if err != nil || quizData.QuizType != constants.QuizTypeSync {
+		return nil
+	}
+
+	scores, err := h.teamRepo.GetTeamScores(ctx, instanceID)
+	if err != nil {
+		log.Printf("Failed to get team scores for instance %s: %v", instanceID, err)
+		return nil
+	}
+
+	rankTeams(scores, quizData.Settings.LeaderboardRanking)
+	return scores
+}
+
+// rankTeams ranks teams by the average score of their members, so teams of
+// different sizes compare fairly, and then by total score.
+func rankTeams(scores []models.TeamScore, ranking string) {
+	slices.SortFunc(scores, func(a, b models.TeamScore) int {
+		return cmp.Or(
+			cmp.Compare(b.AverageScore, a.AverageScore),
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	for i := range scores {
+		if i == 0 {
+			scores[i].Rank = 1
+			continue
+		}
+		prev := scores[i-1]
+		tied := scores[i].AverageScore == prev.AverageScore && scores[i].Score == prev.Score
+		scores[i].Rank = nextRank(prev.Rank, i, tied, ranking)
+	}
+}
+
+func teamLeaderboardPayload(scores []models.TeamScore) TeamLeaderboardPayload {
+	payload := TeamLeaderboardPayload{Teams: make([]TeamLeaderboardEntry, len(scores))}
+	for i, score := range scores {
+		payload.Teams[i] = TeamLeaderboardEntry{
+			Rank:         score.Rank,
+			TeamID:       score.TeamID,
+			Name:         score.Name,
+			Score:        score.Score,
+			AverageScore: score.AverageScore,
+			MemberCount:  score.MemberCount,
+		}
+	}
+	return payload
+}
+
+func (h *Hub) broadcastTeamLeaderboard(ctx context.Context, instanceID string) {
+	if scores := h.getTeamLeaderboard(ctx, instanceID); len(scores) > 0 {
+		h.broadcastToInstance(instanceID, MessageTypeTeamLeaderboard, teamLeaderboardPayload(scores))
+	}
+}
+
+// saveTeamResults stores the final team standings when a sync quiz ends.
+func (h *Hub) saveTeamResults(ctx context.Context, instanceID string) {
+	scores := h.getTeamLeaderboard(ctx, instanceID)
+	if len(scores) == 0 {
+		return
+	}
+
+	if err := h.teamRepo.SaveTeamResults(ctx, instanceID, scores); err != nil {
+		log.Printf("Failed to save team results for instance %s: %v", instanceID, err)
+	}
+	h.broadcastToInstance(instanceID, MessageTypeTeamLeaderboard, teamLeaderboardPayload(scores))
+}
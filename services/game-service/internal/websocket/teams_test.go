@@ -0,0 +1,105 @@
+package websocket
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"game-service/internal/models"
+)
+
+func TestManualTeams(t *testing.T) {
+	participants := []string{"a", "b", "c"}
+
+	teams, err := manualTeams([]TeamInput{
+		{Name: "Red", UserIDs: []string{"a", "c"}},
+		{UserIDs: []string{"b"}},
+	}, participants)
+	if err != nil {
+		t.Fatalf("manualTeams: %v", err)
+	}
+	if teams[0].ID != "team-1" || teams[0].Name != "Red" || !reflect.DeepEqual(teams[0].UserIDs, []string{"a", "c"}) {
+		t.Fatalf("first team = %+v", teams[0])
+	}
+	if teams[1].Name != "Team 2" {
+		t.Fatalf("unnamed team got name %q", teams[1].Name)
+	}
+
+	invalid := map[string][]TeamInput{
+		"single team":    {{Name: "Red", UserIDs: []string{"a"}}},
+		"unknown user":   {{UserIDs: []string{"a"}}, {UserIDs: []string{"x"}}},
+		"user twice":     {{UserIDs: []string{"a"}}, {UserIDs: []string{"a"}}},
+		"duplicate name": {{Name: "Red"}, {Name: "red"}},
+	}
+	for name, inputs := range invalid {
+		if _, err := manualTeams(inputs, participants); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRandomTeamsAreBalanced(t *testing.T) {
+	participants := []string{"a", "b", "c", "d", "e"}
+
+	teams, err := randomTeams(participants, 2)
+	if err != nil {
+		t.Fatalf("randomTeams: %v", err)
+	}
+
+	var all []string
+	for _, team := range teams {
+		all = append(all, team.UserIDs...)
+	}
+	slices.Sort(all)
+	if !reflect.DeepEqual(all, participants) {
+		t.Fatalf("teams cover %v, want %v", all, participants)
+	}
+	if len(teams[0].UserIDs) != 3 || len(teams[1].UserIDs) != 2 {
+		t.Fatalf("team sizes = %d, %d, want 3, 2", len(teams[0].UserIDs), len(teams[1].UserIDs))
+	}
+
+	if _, err := randomTeams(participants, 6); err == nil {
+		t.Fatal("expected an error for more teams than participants")
+	}
+}
+
+func TestGroupTeamsKeepParticipantsOnly(t *testing.T) {
+	hub := &Hub{userLookup: &fakeUserLookup{groups: map[string][]string{
+		"g1": {"a", "b", "outsider"},
+		"g2": {"b", "c"},
+	}}}
+
+	teams, err := hub.groupTeams(context.Background(), []string{"g1", "g2"}, []string{"a", "b", "c", "d"}, "host")
+	if err != nil {
+		t.Fatalf("groupTeams: %v", err)
+	}
+	if teams[0].Name != "Group g1" || !reflect.DeepEqual(teams[0].UserIDs, []string{"a", "b"}) {
+		t.Fatalf("first team = %+v", teams[0])
+	}
+	if !reflect.DeepEqual(teams[1].UserIDs, []string{"c"}) {
+		t.Fatalf("participant in both groups should stay in the first: %+v", teams[1])
+	}
+
+	if _, err := hub.groupTeams(context.Background(), []string{"g1", "missing"}, []string{"a"}, "host"); err == nil {
+		t.Fatal("expected an error for an unknown group")
+	}
+}
+
+func TestRankTeamsByAverageScore(t *testing.T) {
+	scores := []models.TeamScore{
+		{TeamID: "team-1", Name: "Big", Score: 300, AverageScore: 75, MemberCount: 4},
+		{TeamID: "team-2", Name: "Small", Score: 200, AverageScore: 100, MemberCount: 2},
+		{TeamID: "team-3", Name: "Other", Score: 300, AverageScore: 75, MemberCount: 4},
+	}
+
+	rankTeams(scores, "competition")
+
+	got := []string{scores[0].TeamID, scores[1].TeamID, scores[2].TeamID}
+	if want := []string{"team-2", "team-1", "team-3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+	if scores[0].Rank != 1 || scores[1].Rank != 2 || scores[2].Rank != 2 {
+		t.Fatalf("ranks = %d, %d, %d, want 1, 2, 2", scores[0].Rank, scores[1].Rank, scores[2].Rank)
+	}
+}
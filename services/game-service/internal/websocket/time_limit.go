@@ -0,0 +1,150 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"game-service/internal/constants"
+	"game-service/internal/models"
+)
+
+func quizStartedAtKey(instanceID string) string {
+	return fmt.Sprintf("quiz:%s:started_at", instanceID)
+}
+
+func totalTimeLimit(quizData *models.QuizData) time.Duration {
+	return time.Duration(quizData.Settings.TimeLimitTotal) * time.Second
+}
+
+// quizEndsAt returns when the total time limit runs out: for the whole room
+// in sync quizzes, and per session (from GameSession.StartedAt) in async
+// ones. The zero time means there is no limit or the clock has not started.
+func (h *Hub) quizEndsAt(ctx context.Context, instanceID, userID string, quizData *models.QuizData) time.Time {
+	limit := totalTimeLimit(quizData)
+	if limit <= 0 {
+		return time.Time{}
+	}
+
+	if quizData.QuizType == constants.QuizTypeAsync {
+		session, err := h.sessionRepo.GetSession(ctx, instanceID, userID)
+		if err != nil {
+			log.Printf("Failed to get session for total time limit: %v", err)
+			return time.Time{}
+		}
+		return session.StartedAt.Add(limit)
+	}
+
+	if h.redisClient == nil {
+		return time.Time{}
+	}
+
+	startedAtStr, err := h.redisClient.Get(ctx, quizStartedAtKey(instanceID))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var startedAt int64
+	fmt.Sscanf(startedAtStr, "%d", &startedAt)
+	endsAt := time.UnixMilli(startedAt).Add(limit)
+
+	if state, paused := h.getPauseState(ctx, instanceID); paused && state.PausedAt > 0 {
+		endsAt = endsAt.Add(time.Since(time.UnixMilli(state.PausedAt)))
+	}
+	return endsAt
+}
+
+func setTotalRemaining(payload *QuestionPayload, endsAt time.Time) {
+	if endsAt.IsZero() {
+		return
+	}
+	payload.TotalRemainingMs = max(time.Until(endsAt).Milliseconds(), 0)
+}
+
+func (h *Hub) scheduleTotalDeadline(instanceID, userID string, endsAt time.Time) {
+	h.scheduleQuestionDeadline(questionDeadline{
+		InstanceID: instanceID,
+		UserID:     userID,
+		Total:      true,
+	}, max(time.Until(endsAt), 0))
+}
+
+// startRoomClock records when a sync quiz started and schedules its total
+// deadline.
+func (h *Hub) startRoomClock(ctx context.Context, instanceID string, quizData *models.QuizData) {
+	limit := totalTimeLimit(quizData)
+	if limit <= 0 || h.redisClient == nil {
+		return
+	}
+
+	now := time.Now()
+	set, err := h.redisClient.GetClient().SetNX(ctx, quizStartedAtKey(instanceID), now.UnixMilli(), 24*time.Hour).Result()
+	if err != nil {
+		log.Printf("Failed to store quiz start time: %v", err)
+		return
+	}
+	if set {
+		h.scheduleTotalDeadline(instanceID, "", now.Add(limit))
+	}
+}
+
+func (h *Hub) handleTotalTimeExpired(d questionDeadline) {
+	ctx := context.Background()
+
+	if d.UserID == "" {
+		log.Printf("Total time limit reached: instance=%s", d.InstanceID)
+
+		h.broadcastToInstance(d.InstanceID, MessageTypeQuizEnded, QuizEndedPayload{
+			Reason: constants.EndReasonTimeLimit,
+		})
+		if err := h.finishInstance(ctx, d.InstanceID); err != nil {
+			log.Printf("Failed to finish instance %s after total time limit: %v", d.InstanceID, err)
+		}
+		return
+	}
+
+	session, err := h.sessionRepo.GetSession(ctx, d.InstanceID, d.UserID)
+	if err != nil {
+		log.Printf("Failed to get session for total time limit: %v", err)
+		return
+	}
+	if session.Status != constants.SessionStatusInProgress {
+		return
+	}
+
+	log.Printf("Total time limit reached: instance=%s, user=%s", d.InstanceID, d.UserID)
+
+	h.cancelQuestionDeadline(questionDeadline{
+		InstanceID:    d.InstanceID,
+		UserID:        d.UserID,
+		QuestionIndex: session.CurrentQuestionIndex,
+	})
+
+	finished, err := h.sessionRepo.FinishSession(ctx, d.InstanceID, d.UserID, time.Now())
+	if err != nil {
+		log.Printf("Failed to finish session after total time limit: %v", err)
+	} else if finished {
+		if quizData, err := h.getQuizData(ctx, d.InstanceID); err == nil {
+			h.requestGrading(ctx, quizData, session)
+		}
+	}
+
+	h.relay(&relayEnvelope{
+		InstanceID: d.InstanceID,
+		Kind:       relayKindSessionExpired,
+		UserID:     d.UserID,
+	})
+}
+
+func (h *Hub) finishLocalSession(instanceID, userID string) {
+	for _, c := range h.localClients(instanceID) {
+		if c.UserID != userID {
+			continue
+		}
+		c.SendMessage(MessageTypeQuizEnded, QuizEndedPayload{
+			Reason: constants.EndReasonTimeLimit,
+		})
+		h.finishQuiz(c)
+	}
+}
@@ -0,0 +1,65 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"game-service/internal/models"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestRoomClockSchedulesTotalDeadline(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	ctx := context.Background()
+
+	quizData := &models.QuizData{
+		QuizType:  "sync",
+		Questions: []models.Question{{ID: "q1"}},
+		Settings:  models.Settings{TimeLimitTotal: 60},
+	}
+
+	hub.startRoomClock(ctx, "inst-1", quizData)
+	hub.startRoomClock(ctx, "inst-1", quizData)
+
+	endsAt := hub.quizEndsAt(ctx, "inst-1", "", quizData)
+	if left := time.Until(endsAt); left < 59*time.Second || left > 60*time.Second {
+		t.Fatalf("quiz ends in %v, want about 60s", left)
+	}
+
+	var payload QuestionPayload
+	setTotalRemaining(&payload, endsAt)
+	if payload.TotalRemainingMs < 59000 || payload.TotalRemainingMs > 60000 {
+		t.Fatalf("total_remaining_ms = %d, want about 60000", payload.TotalRemainingMs)
+	}
+
+	if got := hub.fireDueDeadlines(ctx, time.Now().Add(30*time.Second)); got != 0 {
+		t.Fatalf("fired %d deadlines before the total limit, want 0", got)
+	}
+
+	count, err := hub.redisClient.GetClient().ZCard(ctx, questionDeadlinesKey).Result()
+	if err != nil || count != 1 {
+		t.Fatalf("pending deadlines = %d (err %v), want 1", count, err)
+	}
+}
+
+func TestUnlimitedQuizHasNoEndTime(t *testing.T) {
+	mr := miniredis.RunT(t)
+	hub := newTestHub(t, mr)
+	ctx := context.Background()
+
+	quizData := &models.QuizData{QuizType: "sync"}
+	hub.startRoomClock(ctx, "inst-1", quizData)
+
+	if endsAt := hub.quizEndsAt(ctx, "inst-1", "", quizData); !endsAt.IsZero() {
+		t.Fatalf("quiz without total limit ends at %v, want zero", endsAt)
+	}
+
+	var payload QuestionPayload
+	setTotalRemaining(&payload, time.Time{})
+	if payload.TotalRemainingMs != 0 {
+		t.Fatalf("total_remaining_ms = %d, want 0", payload.TotalRemainingMs)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	ws "game-service/internal/websocket"
 	"game-service/pkg/cache"
 	"game-service/pkg/database"
+	"game-service/pkg/messaging"
 
 	"github.com/gin-gonic/gin"
 )
@@ -55,12 +56,40 @@ func main() {
 	log.Println("Connected to Quiz Service")
 	defer quizClient.Close()
 
+	var userLookup ws.UserLookup
+	userClient, err := client.NewUserClient(cfg.User.Host, cfg.User.Port)
+	if err != nil {
+		log.Printf("Warning: Failed to connect to User Service, leaderboard shows no names: %v", err)
+	} else {
+		log.Println("Connected to User Service")
+		defer userClient.Close()
+		userLookup = userClient
+	}
+
+	rabbitClient, err := messaging.NewRabbitMQClient(&cfg.RabbitMQ)
+	if err != nil {
+		log.Printf("Warning: Failed to connect to RabbitMQ: %v", err)
+		rabbitClient = nil
+	} else {
+		log.Println("Connected to RabbitMQ")
+		defer rabbitClient.Close()
+	}
+
+	var mqPublisher ws.RabbitMQPublisher
+	if rabbitClient != nil {
+		mqPublisher = rabbitClient
+	}
+
 	sessionRepo := repository.NewSessionRepository(pgClient.GetDB())
 
-	hub := ws.NewHub(quizClient, redisClient, sessionRepo, pgClient.GetDB())
+	hub := ws.NewHub(quizClient, userLookup, redisClient, sessionRepo, pgClient.GetDB(), mqPublisher)
 	go hub.Run()
 	log.Println("WebSocket hub started")
 
+	if rabbitClient != nil {
+		go consumeQueue(context.Background(), rabbitClient, "game.instance_started", hub.HandleInstanceStarted)
+	}
+
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -106,3 +135,19 @@ func main() {
 
 	log.Println("Game service stopped")
 }
+
+func consumeQueue(ctx context.Context, rabbitClient *messaging.RabbitMQClient, queueName string, handler func(context.Context, []byte) error) {
+	msgs, err := rabbitClient.Consume(queueName)
+	if err != nil {
+		log.Printf("Failed to start consumer for queue %s: %v", queueName, err)
+		return
+	}
+
+	log.Printf("Started consumer for queue: %s", queueName)
+
+	for msg := range msgs {
+		if err := handler(ctx, msg.Body); err != nil {
+			log.Printf("Error handling message from %s: %v", queueName, err)
+		}
+	}
+}
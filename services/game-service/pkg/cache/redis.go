@@ -58,6 +58,14 @@ func (c *RedisClient) Exists(ctx context.Context, keys ...string) (int64, error)
 	return c.client.Exists(ctx, keys...).Result()
 }
 
+func (c *RedisClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	return c.client.Publish(ctx, channel, message).Err()
+}
+
+func (c *RedisClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return c.client.Subscribe(ctx, channels...)
+}
+
 func (c *RedisClient) GetClient() *redis.Client {
 	return c.client
 }
\ No newline at end of file
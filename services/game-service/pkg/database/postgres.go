@@ -63,11 +63,34 @@ func (c *PostgresClient) InitSchema(ctx context.Context) error {
 		CREATE INDEX IF NOT EXISTS idx_game_sessions_instance_id ON game_sessions(instance_id);
 		CREATE INDEX IF NOT EXISTS idx_game_sessions_user_id ON game_sessions(user_id);
 		CREATE INDEX IF NOT EXISTS idx_game_sessions_status ON game_sessions(status);
+		ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS question_order JSONB;
+		ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS current_streak INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS best_streak INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS team_id VARCHAR(255);
 	`
 
 	if _, err := c.db.ExecContext(ctx, createGameSessionsTable); err != nil {
 		return fmt.Errorf("failed to create game_sessions table: %w", err)
 	}
 
+	createGameTeamsTable := `
+		CREATE TABLE IF NOT EXISTS game_teams (
+			instance_id VARCHAR(255) NOT NULL,
+			team_id VARCHAR(255) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			score INTEGER NOT NULL DEFAULT 0,
+			average_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+			member_count INTEGER NOT NULL DEFAULT 0,
+			rank INTEGER,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			finished_at TIMESTAMP,
+			PRIMARY KEY (instance_id, team_id)
+		);
+	`
+
+	if _, err := c.db.ExecContext(ctx, createGameTeamsTable); err != nil {
+		return fmt.Errorf("failed to create game_teams table: %w", err)
+	}
+
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+type Config struct {
+	Server   ServerConfig
+	DB       DBConfig
+	RabbitMQ RabbitMQConfig
+	Provider ProviderConfig
+}
+
+type ServerConfig struct {
+	HTTPPort string
+}
+
+type DBConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+}
+
+type RabbitMQConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+}
+
+type ProviderConfig struct {
+	Kind       string // "http" or "stub"
+	APIURL     string
+	APIKey     string
+	Models     []string
+	TimeoutSec int
+}
+
+func Load() *Config {
+	return &Config{
+		Server: ServerConfig{
+			HTTPPort: getEnv("HTTP_PORT", "8080"),
+		},
+		DB: DBConfig{
+			Host:     getEnv("DB_HOST", "postgres"),
+			Port:     getEnv("DB_PORT", "5432"),
+			User:     getEnv("DB_USER", "kollocol"),
+			Password: getEnv("DB_PASSWORD", "kollocol_password"),
+			DBName:   getEnv("DB_NAME", "kollocol"),
+			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+		},
+		RabbitMQ: RabbitMQConfig{
+			Host:     getEnv("RABBITMQ_HOST", "rabbitmq"),
+			Port:     getEnv("RABBITMQ_PORT", "5672"),
+			User:     getEnv("RABBITMQ_USER", "admin"),
+			Password: getEnv("RABBITMQ_PASSWORD", "admin"),
+		},
+		Provider: ProviderConfig{
+			Kind:       getEnv("ML_PROVIDER", "stub"),
+			APIURL:     getEnv("ML_API_URL", "https://api.openai.com/v1"),
+			APIKey:     getEnv("ML_API_KEY", ""),
+			Models:     getEnvAsList("ML_MODELS", []string{"stub"}),
+			TimeoutSec: getEnvAsInt("ML_TIMEOUT_SEC", 30),
+		},
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsInt(key string, defaultValue int) int {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.Atoi(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsList(key string, defaultValue []string) []string {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, v := range strings.Split(valueStr, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
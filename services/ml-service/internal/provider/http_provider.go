@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPProvider talks to any OpenAI-compatible chat completions API.
+type HTTPProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func NewHTTPProvider(baseURL, apiKey, model string, timeout time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (p *HTTPProvider) Name() string {
+	return p.model
+}
+
+func (p *HTTPProvider) Answer(ctx context.Context, question Question) (string, error) {
+	return p.complete(ctx, []chatMessage{
+		{Role: "system", Content: "You are an expert helping a teacher prepare reference answers for a quiz."},
+		{Role: "user", Content: buildPrompt(question)},
+	})
+}
+
+func (p *HTTPProvider) Similarity(ctx context.Context, question Question, answer string, references []string) (float64, error) {
+	content, err := p.complete(ctx, []chatMessage{
+		{Role: "system", Content: "You are an expert helping a teacher grade open quiz answers."},
+		{Role: "user", Content: buildGradingPrompt(question, answer, references)},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("model %s returned an empty grade", p.model)
+	}
+	similarity, err := strconv.ParseFloat(strings.TrimRight(fields[0], ".,"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("model %s returned a non-numeric grade %q", p.model, content)
+	}
+
+	return min(max(similarity, 0), 1), nil
+}
+
+func (p *HTTPProvider) complete(ctx context.Context, messages []chatMessage) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model:    p.model,
+		Messages: messages,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call model %s: %w", p.model, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("model %s returned %d: %s", p.model, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode response from model %s: %w", p.model, err)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("model %s returned no choices", p.model)
+	}
+
+	return strings.TrimSpace(result.Choices[0].Message.Content), nil
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ml-service/config"
+)
+
+type Question struct {
+	ID      string
+	Text    string
+	Type    string
+	Options []string
+}
+
+// AnswerProvider produces a reference answer for a question. Name is the
+// model name the answer is stored under in questions.ai_answer.
+type AnswerProvider interface {
+	Name() string
+	Answer(ctx context.Context, question Question) (string, error)
+}
+
+// NewProviders builds one provider per configured model.
+func NewProviders(cfg *config.ProviderConfig) ([]AnswerProvider, error) {
+	var providers []AnswerProvider
+	for _, model := range cfg.Models {
+		switch cfg.Kind {
+		case "http":
+			providers = append(providers, NewHTTPProvider(cfg.APIURL, cfg.APIKey, model, time.Duration(cfg.TimeoutSec)*time.Second))
+		case "stub":
+			providers = append(providers, NewStubProvider(model))
+		default:
+			return nil, fmt.Errorf("unknown provider kind: %s", cfg.Kind)
+		}
+	}
+	return providers, nil
+}
+
+func buildPrompt(question Question) string {
+	var b strings.Builder
+	b.WriteString("Answer the quiz question below. Reply with the answer only, without explanations.\n\n")
+	b.WriteString("Question: ")
+	b.WriteString(question.Text)
+	b.WriteString("\n")
+
+	if len(question.Options) > 0 {
+		b.WriteString("Options:\n")
+		for i, option := range question.Options {
+			fmt.Fprintf(&b, "%d. %s\n", i, option)
+		}
+		b.WriteString("Reply with the number of the correct option.\n")
+	}
+
+	return b.String()
+}
+
+func buildGradingPrompt(question Question, answer string, references []string) string {
+	var b strings.Builder
+	b.WriteString("Rate how well the student's answer matches the reference answers. ")
+	b.WriteString("Reply with a single number between 0 and 1, where 1 means fully correct.\n\n")
+	b.WriteString("Question: ")
+	b.WriteString(question.Text)
+	b.WriteString("\n")
+	for _, ref := range references {
+		b.WriteString("Reference answer: ")
+		b.WriteString(ref)
+		b.WriteString("\n")
+	}
+	b.WriteString("Student answer: ")
+	b.WriteString(answer)
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStubProviderIsDeterministic(t *testing.T) {
+	p := NewStubProvider("stub")
+	ctx := context.Background()
+	question := Question{ID: "q1", Text: "Pick one", Type: "multiple_choice", Options: []string{"a", "b", "c"}}
+
+	first, err := p.Answer(ctx, question)
+	if err != nil {
+		t.Fatalf("Answer() error = %v", err)
+	}
+	again, _ := p.Answer(ctx, question)
+	if first != again {
+		t.Fatalf("stub answers differ: %q vs %q", first, again)
+	}
+	if first != "0" && first != "1" && first != "2" {
+		t.Fatalf("stub answer %q is not an option index", first)
+	}
+}
+
+func TestHTTPProviderParsesChatCompletion(t *testing.T) {
+	var got chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("path = %s, want /chat/completions", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  Paris \n"}}]}`))
+	}))
+	defer server.Close()
+
+	p := NewHTTPProvider(server.URL+"/", "secret", "test-model", time.Second)
+	answer, err := p.Answer(context.Background(), Question{ID: "q1", Text: "Capital of France?", Type: "open"})
+	if err != nil {
+		t.Fatalf("Answer() error = %v", err)
+	}
+	if answer != "Paris" {
+		t.Fatalf("answer = %q, want %q", answer, "Paris")
+	}
+	if got.Model != "test-model" || len(got.Messages) != 2 {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+}
+
+func TestHTTPProviderReportsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	p := NewHTTPProvider(server.URL, "", "test-model", time.Second)
+	if _, err := p.Answer(context.Background(), Question{Text: "?"}); err == nil {
+		t.Fatal("Answer() succeeded on a 429 response")
+	}
+}
+
+func TestLexicalSimilarity(t *testing.T) {
+	tests := []struct {
+		answer, reference string
+		min, max          float64
+	}{
+		{"Paris", "paris.", 1, 1},
+		{"The capital is Paris", "Paris", 0.4, 0.9},
+		{"Pariss", "Paris", 0.8, 0.99},
+		{"Столица — Москва", "москва", 0.5, 0.9},
+		{"Berlin", "Paris", 0, 0.1},
+		{"", "Paris", 0, 0},
+	}
+
+	scorer := NewLexicalScorer()
+	for _, tt := range tests {
+		got, err := scorer.Similarity(context.Background(), Question{}, tt.answer, []string{tt.reference})
+		if err != nil {
+			t.Fatalf("Similarity() error = %v", err)
+		}
+		if got < tt.min || got > tt.max {
+			t.Errorf("Similarity(%q, %q) = %.2f, want in [%.2f, %.2f]", tt.answer, tt.reference, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestNewScorerFallsBackToLexical(t *testing.T) {
+	if got := NewScorer([]AnswerProvider{NewStubProvider("stub")}).Name(); got != "lexical" {
+		t.Fatalf("scorer = %s, want lexical", got)
+	}
+	http := NewHTTPProvider("http://localhost", "", "test-model", time.Second)
+	if got := NewScorer([]AnswerProvider{http}).Name(); got != "test-model" {
+		t.Fatalf("scorer = %s, want test-model", got)
+	}
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// SimilarityScorer rates how close a participant's answer is to the
+// reference answers, from 0 (unrelated) to 1 (equivalent).
+type SimilarityScorer interface {
+	Name() string
+	Similarity(ctx context.Context, question Question, answer string, references []string) (float64, error)
+}
+
+// NewScorer returns the first configured model when an HTTP provider is set
+// up and the lexical scorer otherwise.
+func NewScorer(providers []AnswerProvider) SimilarityScorer {
+	for _, p := range providers {
+		if scorer, ok := p.(SimilarityScorer); ok {
+			return scorer
+		}
+	}
+	return NewLexicalScorer()
+}
+
+// LexicalScorer compares answers by their words and character bigrams. It
+// needs no model and is used when none is configured or the model fails.
+type LexicalScorer struct{}
+
+func NewLexicalScorer() *LexicalScorer {
+	return &LexicalScorer{}
+}
+
+func (s *LexicalScorer) Name() string {
+	return "lexical"
+}
+
+func (s *LexicalScorer) Similarity(ctx context.Context, question Question, answer string, references []string) (float64, error) {
+	best := 0.0
+	for _, ref := range references {
+		if sim := lexicalSimilarity(answer, ref); sim > best {
+			best = sim
+		}
+	}
+	return best, nil
+}
+
+func lexicalSimilarity(a, b string) float64 {
+	aTokens := tokenize(a)
+	bTokens := tokenize(b)
+	if len(aTokens) == 0 || len(bTokens) == 0 {
+		return 0
+	}
+
+	aJoined := strings.Join(aTokens, " ")
+	bJoined := strings.Join(bTokens, " ")
+	if aJoined == bJoined {
+		return 1
+	}
+
+	return max(tokenF1(aTokens, bTokens), bigramDice(aJoined, bJoined))
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// tokenF1 is the harmonic mean of how much of the answer matches the
+// reference and how much of the reference the answer covers.
+func tokenF1(answer, reference []string) float64 {
+	refSet := make(map[string]bool, len(reference))
+	for _, t := range reference {
+		refSet[t] = true
+	}
+	answerSet := make(map[string]bool, len(answer))
+	for _, t := range answer {
+		answerSet[t] = true
+	}
+
+	common := 0
+	for t := range answerSet {
+		if refSet[t] {
+			common++
+		}
+	}
+	if common == 0 {
+		return 0
+	}
+
+	precision := float64(common) / float64(len(answerSet))
+	recall := float64(common) / float64(len(refSet))
+	return 2 * precision * recall / (precision + recall)
+}
+
+// bigramDice catches typos and word forms that token matching misses.
+func bigramDice(a, b string) float64 {
+	aBigrams := bigrams(a)
+	bBigrams := bigrams(b)
+	if len(aBigrams) == 0 || len(bBigrams) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, n := range aBigrams {
+		total += n
+	}
+	for _, n := range bBigrams {
+		total += n
+	}
+
+	common := 0
+	for bg, n := range aBigrams {
+		common += min(n, bBigrams[bg])
+	}
+	return 2 * float64(common) / float64(total)
+}
+
+func bigrams(s string) map[string]int {
+	runes := []rune(s)
+	result := make(map[string]int)
+	for i := 0; i+1 < len(runes); i++ {
+		result[string(runes[i:i+2])]++
+	}
+	return result
+}
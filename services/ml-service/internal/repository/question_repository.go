@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// QuestionRepository writes AI answers into the questions table owned by
+// quiz-service.
+type QuestionRepository struct {
+	db *sql.DB
+}
+
+func NewQuestionRepository(db *sql.DB) *QuestionRepository {
+	return &QuestionRepository{db: db}
+}
+
+// ErrQuestionNotFound is returned when the question was deleted before its
+// answer was generated.
+var ErrQuestionNotFound = errors.New("question not found")
+
+// SaveAIAnswer merges the answer into questions.ai_answer under the model
+// name, keeping answers from other models.
+func (r *QuestionRepository) SaveAIAnswer(ctx context.Context, questionID, model, answer string) error {
+	query := `
+		UPDATE questions
+		SET ai_answer = CASE
+			WHEN jsonb_typeof(ai_answer) = 'object' THEN ai_answer
+			ELSE '{}'::jsonb
+		END || jsonb_build_object($2::text, $3::text)
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, questionID, model, answer)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrQuestionNotFound
+	}
+
+	return nil
+}
+
+// GetAIAnswers returns the stored AI answers of each question, in no
+// particular model order.
+func (r *QuestionRepository) GetAIAnswers(ctx context.Context, questionIDs []string) (map[string][]string, error) {
+	query := `
+		SELECT id, ai_answer
+		FROM questions
+		WHERE id = ANY($1) AND jsonb_typeof(ai_answer) = 'object'
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(questionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]string)
+	for rows.Next() {
+		var questionID, aiAnswerJSON string
+		if err := rows.Scan(&questionID, &aiAnswerJSON); err != nil {
+			return nil, err
+		}
+
+		var byModel map[string]string
+		if err := json.Unmarshal([]byte(aiAnswerJSON), &byModel); err != nil {
+			continue
+		}
+		for _, answer := range byModel {
+			if answer != "" {
+				result[questionID] = append(result[questionID], answer)
+			}
+		}
+	}
+
+	return result, rows.Err()
+}
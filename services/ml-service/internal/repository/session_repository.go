@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SessionRepository attaches grading suggestions to the game_sessions table
+// owned by game-service.
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+type GradingSuggestion struct {
+	Similarity     float64 `json:"similarity"`
+	SuggestedScore int     `json:"suggested_score"`
+	Model          string  `json:"model"`
+}
+
+// ErrSessionNotFound is returned when the session was removed before it was
+// graded.
+var ErrSessionNotFound = errors.New("session not found")
+
+// SaveSuggestions stores a suggestion next to each answer. Answers are kept as
+// raw JSON so fields owned by other services survive the rewrite.
+func (r *SessionRepository) SaveSuggestions(ctx context.Context, instanceID, userID string, suggestions map[string]GradingSuggestion) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var answersJSON string
+	err = tx.QueryRowContext(ctx,
+		`SELECT answers FROM game_sessions WHERE instance_id = $1 AND user_id = $2 FOR UPDATE`,
+		instanceID, userID,
+	).Scan(&answersJSON)
+	if err == sql.ErrNoRows {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var answers []map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(answersJSON), &answers); err != nil {
+		return fmt.Errorf("failed to parse answers: %w", err)
+	}
+
+	for _, answer := range answers {
+		var questionID string
+		if err := json.Unmarshal(answer["question_id"], &questionID); err != nil {
+			continue
+		}
+		suggestion, ok := suggestions[questionID]
+		if !ok {
+			continue
+		}
+		suggestionJSON, err := json.Marshal(suggestion)
+		if err != nil {
+			return err
+		}
+		answer["ai_suggestion"] = suggestionJSON
+	}
+
+	updated, err := json.Marshal(answers)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE game_sessions SET answers = $1 WHERE instance_id = $2 AND user_id = $3`,
+		string(updated), instanceID, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"ml-service/internal/provider"
+	"ml-service/internal/repository"
+)
+
+type AnswerStore interface {
+	SaveAIAnswer(ctx context.Context, questionID, model, answer string) error
+}
+
+type AnswerWorker struct {
+	store     AnswerStore
+	providers []provider.AnswerProvider
+}
+
+func NewAnswerWorker(store AnswerStore, providers []provider.AnswerProvider) *AnswerWorker {
+	return &AnswerWorker{
+		store:     store,
+		providers: providers,
+	}
+}
+
+type QuestionData struct {
+	QuestionID string   `json:"question_id"`
+	Text       string   `json:"text"`
+	Type       string   `json:"type"`
+	Options    []string `json:"options,omitempty"`
+}
+
+type AIAnswerRequestEvent struct {
+	TemplateID string         `json:"template_id"`
+	Questions  []QuestionData `json:"questions"`
+	Models     []string       `json:"models"`
+}
+
+// HandleAIAnswerRequest generates answers for every question with every
+// requested model. A failing model is logged and skipped so one bad provider
+// does not keep the message in the queue; only storage errors are retried.
+func (w *AnswerWorker) HandleAIAnswerRequest(ctx context.Context, data []byte) error {
+	var event AIAnswerRequestEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal ai_answer_request event: %w", err)
+	}
+
+	providers := w.selectProviders(event.Models)
+	if len(providers) == 0 {
+		log.Printf("No answer providers available for template %s (requested %v)", event.TemplateID, event.Models)
+		return nil
+	}
+
+	for _, q := range event.Questions {
+		question := provider.Question{
+			ID:      q.QuestionID,
+			Text:    q.Text,
+			Type:    q.Type,
+			Options: q.Options,
+		}
+
+		for _, p := range providers {
+			answer, err := p.Answer(ctx, question)
+			if err != nil {
+				log.Printf("Model %s failed to answer question %s: %v", p.Name(), q.QuestionID, err)
+				continue
+			}
+
+			err = w.store.SaveAIAnswer(ctx, q.QuestionID, p.Name(), answer)
+			if errors.Is(err, repository.ErrQuestionNotFound) {
+				log.Printf("Question %s no longer exists, skipping", q.QuestionID)
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to save ai answer for question %s: %w", q.QuestionID, err)
+			}
+		}
+	}
+
+	log.Printf("AI answers generated for template %s (%d questions)", event.TemplateID, len(event.Questions))
+	return nil
+}
+
+// selectProviders returns the providers for the requested models, or all
+// configured providers when the event does not name any.
+func (w *AnswerWorker) selectProviders(models []string) []provider.AnswerProvider {
+	if len(models) == 0 {
+		return w.providers
+	}
+
+	var selected []provider.AnswerProvider
+	for _, model := range models {
+		found := false
+		for _, p := range w.providers {
+			if p.Name() == model {
+				selected = append(selected, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Printf("Model %s is not configured, skipping", model)
+		}
+	}
+	return selected
+}
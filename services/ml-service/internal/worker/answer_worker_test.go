@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ml-service/internal/provider"
+)
+
+type memoryStore struct {
+	answers map[string]map[string]string
+}
+
+func (s *memoryStore) SaveAIAnswer(ctx context.Context, questionID, model, answer string) error {
+	if s.answers[questionID] == nil {
+		s.answers[questionID] = make(map[string]string)
+	}
+	s.answers[questionID][model] = answer
+	return nil
+}
+
+type failingProvider struct{}
+
+func (failingProvider) Name() string { return "broken" }
+
+func (failingProvider) Answer(ctx context.Context, question provider.Question) (string, error) {
+	return "", errors.New("unavailable")
+}
+
+func TestHandleAIAnswerRequestStoresAnswerPerModel(t *testing.T) {
+	store := &memoryStore{answers: make(map[string]map[string]string)}
+	w := NewAnswerWorker(store, []provider.AnswerProvider{
+		provider.NewStubProvider("stub-a"),
+		provider.NewStubProvider("stub-b"),
+		failingProvider{},
+	})
+
+	event := `{"template_id":"t1","questions":[
+		{"question_id":"q1","text":"2+2?","type":"open"},
+		{"question_id":"q2","text":"Pick","type":"multiple_choice","options":["x","y"]}
+	],"models":[]}`
+	if err := w.HandleAIAnswerRequest(context.Background(), []byte(event)); err != nil {
+		t.Fatalf("HandleAIAnswerRequest() error = %v", err)
+	}
+
+	for _, qid := range []string{"q1", "q2"} {
+		if len(store.answers[qid]) != 2 {
+			t.Fatalf("answers for %s = %v, want one per working model", qid, store.answers[qid])
+		}
+	}
+}
+
+func TestHandleAIAnswerRequestUsesRequestedModels(t *testing.T) {
+	store := &memoryStore{answers: make(map[string]map[string]string)}
+	w := NewAnswerWorker(store, []provider.AnswerProvider{
+		provider.NewStubProvider("stub-a"),
+		provider.NewStubProvider("stub-b"),
+	})
+
+	event := `{"template_id":"t1","questions":[{"question_id":"q1","text":"2+2?","type":"open"}],"models":["stub-b","unknown"]}`
+	if err := w.HandleAIAnswerRequest(context.Background(), []byte(event)); err != nil {
+		t.Fatalf("HandleAIAnswerRequest() error = %v", err)
+	}
+
+	if _, ok := store.answers["q1"]["stub-a"]; ok || len(store.answers["q1"]) != 1 {
+		t.Fatalf("answers = %v, want only stub-b", store.answers["q1"])
+	}
+}
@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+
+	"ml-service/internal/provider"
+	"ml-service/internal/repository"
+)
+
+type SuggestionStore interface {
+	SaveSuggestions(ctx context.Context, instanceID, userID string, suggestions map[string]repository.GradingSuggestion) error
+}
+
+type AIAnswerSource interface {
+	GetAIAnswers(ctx context.Context, questionIDs []string) (map[string][]string, error)
+}
+
+type GradingWorker struct {
+	store     SuggestionStore
+	aiAnswers AIAnswerSource
+	scorer    provider.SimilarityScorer
+	fallback  provider.SimilarityScorer
+}
+
+func NewGradingWorker(store SuggestionStore, aiAnswers AIAnswerSource, scorer provider.SimilarityScorer) *GradingWorker {
+	return &GradingWorker{
+		store:     store,
+		aiAnswers: aiAnswers,
+		scorer:    scorer,
+		fallback:  provider.NewLexicalScorer(),
+	}
+}
+
+type GradingAnswerData struct {
+	QuestionID    string `json:"question_id"`
+	QuestionText  string `json:"question_text"`
+	Answer        string `json:"answer"`
+	CorrectAnswer string `json:"correct_answer"`
+	MaxScore      int    `json:"max_score"`
+}
+
+type GradingRequestEvent struct {
+	InstanceID string              `json:"instance_id"`
+	UserID     string              `json:"user_id"`
+	Answers    []GradingAnswerData `json:"answers"`
+}
+
+// HandleGradingRequest compares each open answer with the template's correct
+// answer and the stored AI answers, and saves a suggested score for the host.
+func (w *GradingWorker) HandleGradingRequest(ctx context.Context, data []byte) error {
+	var event GradingRequestEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal grading_request event: %w", err)
+	}
+
+	if len(event.Answers) == 0 {
+		return nil
+	}
+
+	questionIDs := make([]string, len(event.Answers))
+	for i, a := range event.Answers {
+		questionIDs[i] = a.QuestionID
+	}
+	aiAnswers, err := w.aiAnswers.GetAIAnswers(ctx, questionIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get ai answers: %w", err)
+	}
+
+	suggestions := make(map[string]repository.GradingSuggestion, len(event.Answers))
+	for _, a := range event.Answers {
+		var references []string
+		if a.CorrectAnswer != "" {
+			references = append(references, a.CorrectAnswer)
+		}
+		references = append(references, aiAnswers[a.QuestionID]...)
+		if len(references) == 0 {
+			continue
+		}
+
+		suggestions[a.QuestionID] = w.suggest(ctx, a, references)
+	}
+
+	err = w.store.SaveSuggestions(ctx, event.InstanceID, event.UserID, suggestions)
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		log.Printf("Session of user %s in instance %s no longer exists, skipping", event.UserID, event.InstanceID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save grading suggestions: %w", err)
+	}
+
+	log.Printf("Grading suggestions saved: instance=%s, user=%s, answers=%d", event.InstanceID, event.UserID, len(suggestions))
+	return nil
+}
+
+func (w *GradingWorker) suggest(ctx context.Context, a GradingAnswerData, references []string) repository.GradingSuggestion {
+	question := provider.Question{ID: a.QuestionID, Text: a.QuestionText}
+
+	scorer := w.scorer
+	similarity, err := scorer.Similarity(ctx, question, a.Answer, references)
+	if err != nil {
+		log.Printf("Model %s failed to grade question %s, using %s: %v", scorer.Name(), a.QuestionID, w.fallback.Name(), err)
+		scorer = w.fallback
+		similarity, _ = scorer.Similarity(ctx, question, a.Answer, references)
+	}
+
+	return repository.GradingSuggestion{
+		Similarity:     math.Round(similarity*100) / 100,
+		SuggestedScore: int(math.Round(similarity * float64(a.MaxScore))),
+		Model:          scorer.Name(),
+	}
+}
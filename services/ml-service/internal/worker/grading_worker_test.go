@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ml-service/internal/provider"
+	"ml-service/internal/repository"
+)
+
+type memorySuggestionStore struct {
+	saved map[string]repository.GradingSuggestion
+}
+
+func (s *memorySuggestionStore) SaveSuggestions(ctx context.Context, instanceID, userID string, suggestions map[string]repository.GradingSuggestion) error {
+	s.saved = suggestions
+	return nil
+}
+
+type staticAIAnswers map[string][]string
+
+func (a staticAIAnswers) GetAIAnswers(ctx context.Context, questionIDs []string) (map[string][]string, error) {
+	return a, nil
+}
+
+type failingScorer struct{}
+
+func (failingScorer) Name() string { return "broken" }
+
+func (failingScorer) Similarity(ctx context.Context, question provider.Question, answer string, references []string) (float64, error) {
+	return 0, errors.New("unavailable")
+}
+
+func TestHandleGradingRequestSuggestsScores(t *testing.T) {
+	store := &memorySuggestionStore{}
+	aiAnswers := staticAIAnswers{"q2": {"Photosynthesis turns light into chemical energy"}}
+	w := NewGradingWorker(store, aiAnswers, failingScorer{})
+
+	event := `{"instance_id":"i1","user_id":"u1","answers":[
+		{"question_id":"q1","answer":"paris","correct_answer":"Paris","max_score":10},
+		{"question_id":"q2","answer":"it turns light into chemical energy","max_score":4},
+		{"question_id":"q3","answer":"anything","max_score":5}
+	]}`
+	if err := w.HandleGradingRequest(context.Background(), []byte(event)); err != nil {
+		t.Fatalf("HandleGradingRequest() error = %v", err)
+	}
+
+	q1 := store.saved["q1"]
+	if q1.SuggestedScore != 10 || q1.Similarity != 1 || q1.Model != "lexical" {
+		t.Fatalf("q1 suggestion = %+v, want full score from lexical fallback", q1)
+	}
+	if q2 := store.saved["q2"]; q2.SuggestedScore < 2 || q2.SuggestedScore > 4 {
+		t.Fatalf("q2 suggestion = %+v, want a score graded against the AI answer", q2)
+	}
+	if _, ok := store.saved["q3"]; ok {
+		t.Fatal("q3 has no reference answer but got a suggestion")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"ml-service/config"
+	"ml-service/internal/provider"
+	"ml-service/internal/repository"
+	"ml-service/internal/worker"
+	"ml-service/pkg/database"
+	"ml-service/pkg/messaging"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	cfg := config.Load()
+	log.Println("Configuration loaded")
+
+	pgClient, err := database.NewPostgresClient(&cfg.DB)
+	if err != nil {
+		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
+	}
+	log.Println("Connected to PostgreSQL")
+	defer pgClient.Close()
+
+	rabbitClient, err := messaging.NewRabbitMQClient(&cfg.RabbitMQ)
+	if err != nil {
+		log.Fatalf("Failed to connect to RabbitMQ: %v", err)
+	}
+	log.Println("Connected to RabbitMQ")
+	defer rabbitClient.Close()
+
+	providers, err := provider.NewProviders(&cfg.Provider)
+	if err != nil {
+		log.Fatalf("Failed to initialize answer providers: %v", err)
+	}
+	log.Printf("Answer providers initialized: %s %v", cfg.Provider.Kind, cfg.Provider.Models)
+
+	if os.Getenv("GIN_MODE") == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+
+	router.Use(gin.Logger())
+	router.Use(gin.Recovery())
+
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "ok",
+			"service": "ml-service",
+		})
+	})
+
+	httpAddr := ":" + cfg.Server.HTTPPort
+	log.Printf("ML Service HTTP server starting on port %s...", cfg.Server.HTTPPort)
+	go func() {
+		if err := router.Run(httpAddr); err != nil {
+			log.Fatalf("Failed to start HTTP server: %v", err)
+		}
+	}()
+
+	questionRepo := repository.NewQuestionRepository(pgClient.GetDB())
+	sessionRepo := repository.NewSessionRepository(pgClient.GetDB())
+	answerWorker := worker.NewAnswerWorker(questionRepo, providers)
+	gradingWorker := worker.NewGradingWorker(sessionRepo, questionRepo, provider.NewScorer(providers))
+
+	log.Println("Starting RabbitMQ consumers...")
+	ctx := context.Background()
+	go consumeQueue(ctx, rabbitClient, "ml.ai_answer_requests", answerWorker.HandleAIAnswerRequest)
+	go consumeQueue(ctx, rabbitClient, "ml.grading_requests", gradingWorker.HandleGradingRequest)
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("ML Service stopped")
+}
+
+func consumeQueue(ctx context.Context, rabbitClient *messaging.RabbitMQClient, queueName string, handler func(context.Context, []byte) error) {
+	msgs, err := rabbitClient.Consume(queueName)
+	if err != nil {
+		log.Printf("Failed to start consumer for queue %s: %v", queueName, err)
+		return
+	}
+
+	log.Printf("Started consumer for queue: %s", queueName)
+
+	for msg := range msgs {
+		if err := handler(ctx, msg.Body); err != nil {
+			log.Printf("Error handling message from %s: %v", queueName, err)
+			msg.Nack(false, true)
+		} else {
+			msg.Ack(false)
+		}
+	}
+}
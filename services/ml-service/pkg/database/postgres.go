@@ -0,0 +1,47 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"ml-service/config"
+
+	_ "github.com/lib/pq"
+)
+
+type PostgresClient struct {
+	db     *sql.DB
+	config *config.DBConfig
+}
+
+func NewPostgresClient(cfg *config.DBConfig) (*PostgresClient, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &PostgresClient{
+		db:     db,
+		config: cfg,
+	}, nil
+}
+
+func (c *PostgresClient) Close() error {
+	if c.db != nil {
+		return c.db.Close()
+	}
+	return nil
+}
+
+func (c *PostgresClient) GetDB() *sql.DB {
+	return c.db
+}
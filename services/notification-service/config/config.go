@@ -10,6 +10,7 @@ type Config struct {
 	DB       DBConfig
 	RabbitMQ RabbitMQConfig
 	SMTP     SMTPConfig
+	User     UserServiceConfig
 }
 
 type ServerConfig struct {
@@ -41,6 +42,11 @@ type SMTPConfig struct {
 	From     string
 }
 
+type UserServiceConfig struct {
+	Host string
+	Port string
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -68,6 +74,10 @@ func Load() *Config {
 			Password: getEnv("SMTP_PASSWORD", ""),
 			From:     getEnv("SMTP_FROM", "noreply@kollocol.com"),
 		},
+		User: UserServiceConfig{
+			Host: getEnv("USER_SERVICE_HOST", "localhost"),
+			Port: getEnv("USER_SERVICE_PORT", "50051"),
+		},
 	}
 }
 
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	pb "notification-service/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// settingsCacheTTL bounds how long a changed preference can take to apply.
+// A burst of events for the same users hits user-service only once.
+const settingsCacheTTL = time.Minute
+
+type UserClient struct {
+	client pb.UserServiceClient
+	conn   *grpc.ClientConn
+	cache  *settingsCache
+}
+
+func NewUserClient(host, port string) (*UserClient, error) {
+	address := fmt.Sprintf("%s:%s", host, port)
+
+	conn, err := grpc.NewClient(
+		address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user client for %s: %w", address, err)
+	}
+
+	log.Printf("User Service client initialized for %s", address)
+
+	return &UserClient{
+		client: pb.NewUserServiceClient(conn),
+		conn:   conn,
+		cache:  newSettingsCache(settingsCacheTTL),
+	}, nil
+}
+
+func (c *UserClient) Close() error {
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// GetNotificationSettings returns the user's notification preferences,
+// served from a short-lived cache.
+func (c *UserClient) GetNotificationSettings(ctx context.Context, userID string) (*pb.NotificationSettings, error) {
+	if settings, ok := c.cache.get(userID, time.Now()); ok {
+		return settings, nil
+	}
+
+	resp, err := c.client.GetNotificationSettings(ctx, &pb.GetNotificationSettingsRequest{UserId: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get notification settings: %s", resp.Message)
+	}
+
+	c.cache.put(userID, resp.Settings, time.Now())
+	return resp.Settings, nil
+}
+
+// GetUserIDByEmail resolves an email address to a user ID.
+func (c *UserClient) GetUserIDByEmail(ctx context.Context, email string) (string, error) {
+	resp, err := c.client.GetProfileByEmail(ctx, &pb.GetProfileByEmailRequest{Email: email})
+	if err != nil {
+		return "", fmt.Errorf("failed to get user by email: %w", err)
+	}
+	if !resp.Success {
+		return "", fmt.Errorf("failed to get user by email: %s", resp.Message)
+	}
+
+	return resp.User.Id, nil
+}
+
+func (c *UserClient) GetUsersByIDs(ctx context.Context, userIDs []string) (map[string]*pb.User, error) {
+	resp, err := c.client.GetUsersByIDs(ctx, &pb.GetUsersByIDsRequest{UserIds: userIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get users: %s", resp.Message)
+	}
+
+	users := make(map[string]*pb.User, len(resp.Users))
+	for _, u := range resp.Users {
+		users[u.Id] = u
+	}
+	return users, nil
+}
+
+type cachedSettings struct {
+	settings  *pb.NotificationSettings
+	expiresAt time.Time
+}
+
+type settingsCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]cachedSettings
+	lastSweep time.Time
+}
+
+func newSettingsCache(ttl time.Duration) *settingsCache {
+	return &settingsCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedSettings),
+	}
+}
+
+func (c *settingsCache) get(userID string, now time.Time) (*pb.NotificationSettings, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userID]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(entry.expiresAt) {
+		delete(c.entries, userID)
+		return nil, false
+	}
+	return entry.settings, true
+}
+
+func (c *settingsCache) put(userID string, settings *pb.NotificationSettings, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop expired entries once per TTL so users who stop receiving events
+	// do not stay in memory.
+	if now.Sub(c.lastSweep) >= c.ttl {
+		for id, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, id)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	c.entries[userID] = cachedSettings{
+		settings:  settings,
+		expiresAt: now.Add(c.ttl),
+	}
+}
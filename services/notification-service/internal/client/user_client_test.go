@@ -0,0 +1,44 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	pb "notification-service/proto"
+)
+
+func TestSettingsCacheExpires(t *testing.T) {
+	cache := newSettingsCache(time.Minute)
+	now := time.Now()
+	settings := &pb.NotificationSettings{UserId: "u1", NewQuizzes: true}
+
+	if _, ok := cache.get("u1", now); ok {
+		t.Fatal("empty cache returned settings")
+	}
+
+	cache.put("u1", settings, now)
+	if got, ok := cache.get("u1", now.Add(59*time.Second)); !ok || got != settings {
+		t.Fatalf("get before expiry = %v, %v", got, ok)
+	}
+	if _, ok := cache.get("u1", now.Add(time.Minute)); ok {
+		t.Fatal("settings returned after expiry")
+	}
+	if len(cache.entries) != 0 {
+		t.Fatalf("expired entry kept: %d entries", len(cache.entries))
+	}
+}
+
+func TestSettingsCacheSweepsExpiredEntries(t *testing.T) {
+	cache := newSettingsCache(time.Minute)
+	now := time.Now()
+
+	cache.put("stale", &pb.NotificationSettings{}, now)
+	cache.put("fresh", &pb.NotificationSettings{}, now.Add(2*time.Minute))
+
+	if _, ok := cache.entries["stale"]; ok {
+		t.Fatal("expired entry was not swept")
+	}
+	if _, ok := cache.entries["fresh"]; !ok {
+		t.Fatal("fresh entry missing")
+	}
+}
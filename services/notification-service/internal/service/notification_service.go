@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"notification-service/internal/repository"
@@ -12,19 +14,47 @@ import (
 	pb "notification-service/proto"
 )
 
+type UserClient interface {
+	GetNotificationSettings(ctx context.Context, userID string) (*pb.NotificationSettings, error)
+	GetUserIDByEmail(ctx context.Context, email string) (string, error)
+	GetUsersByIDs(ctx context.Context, userIDs []string) (map[string]*pb.User, error)
+}
+
 type NotificationService struct {
 	pb.UnimplementedNotificationServiceServer
 	repo       *repository.NotificationRepository
 	smtpClient *email.SMTPClient
+	userClient UserClient
 }
 
-func NewNotificationService(db *sql.DB, smtpClient *email.SMTPClient) *NotificationService {
+func NewNotificationService(db *sql.DB, smtpClient *email.SMTPClient, userClient UserClient) *NotificationService {
 	return &NotificationService{
 		repo:       repository.NewNotificationRepository(db),
 		smtpClient: smtpClient,
+		userClient: userClient,
 	}
 }
 
+// deliveryChannels reports whether the user's preferences allow the kind of
+// notification that enabled selects, e.g. (*pb.NotificationSettings).GetNewQuizzes,
+// in the app and by email. A nil enabled checks the channels only.
+// Notifications are delivered when the preferences cannot be loaded.
+func (s *NotificationService) deliveryChannels(ctx context.Context, userID string, enabled func(*pb.NotificationSettings) bool) (inApp, byEmail bool) {
+	if s.userClient == nil {
+		return true, true
+	}
+
+	settings, err := s.userClient.GetNotificationSettings(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to get notification settings of user %s: %v", userID, err)
+		return true, true
+	}
+	if enabled != nil && !enabled(settings) {
+		return false, false
+	}
+	return settings.GetInAppEnabled(), settings.GetEmailEnabled()
+}
+
 func (s *NotificationService) GetNotifications(ctx context.Context, req *pb.GetNotificationsRequest) (*pb.GetNotificationsResponse, error) {
 	limit := max(req.Limit, 1)
 	offset := max(req.Offset, 0)
@@ -113,6 +143,16 @@ func (s *NotificationService) HandleGroupInvite(ctx context.Context, data []byte
 		return err
 	}
 
+	if s.userClient != nil {
+		// Invitees without an account yet have nothing opted out
+		if userID, err := s.userClient.GetUserIDByEmail(ctx, event.InviteeEmail); err == nil {
+			if _, byEmail := s.deliveryChannels(ctx, userID, (*pb.NotificationSettings).GetGroupInvites); !byEmail {
+				log.Printf("Skipping group invite to %s: group invite emails are turned off", event.InviteeEmail)
+				return nil
+			}
+		}
+	}
+
 	log.Printf("Sending group invite to %s for group %s", event.InviteeEmail, event.GroupName)
 	return s.smtpClient.SendGroupInvite(event.InviteeEmail, event.GroupName, event.InviterName)
 }
@@ -133,12 +173,91 @@ func (s *NotificationService) HandleQuizCreated(ctx context.Context, data []byte
 
 	log.Printf("Processing quiz_created event for instance %s", event.InstanceID)
 
-	// Create in-app notifications for participants
+	users := s.lookupUsers(ctx, append([]string{event.CreatorID}, event.Participants...))
+	creatorName := "Someone"
+	if creator, ok := users[event.CreatorID]; ok {
+		if name := strings.TrimSpace(creator.FirstName + " " + creator.LastName); name != "" {
+			creatorName = name
+		}
+	}
+
+	// Create in-app notifications and emails for participants
 	for _, userID := range event.Participants {
+		if userID == event.CreatorID {
+			continue
+		}
+		inApp, byEmail := s.deliveryChannels(ctx, userID, (*pb.NotificationSettings).GetNewQuizzes)
+
+		if inApp {
+			notification := &repository.Notification{
+				UserID:  userID,
+				Type:    "quiz_created",
+				Title:   "New Quiz Available",
+				Content: event.Title,
+				IsRead:  false,
+			}
+
+			if err := s.repo.CreateNotification(ctx, notification); err != nil {
+				log.Printf("Failed to create notification for user %s: %v", userID, err)
+			}
+		}
+
+		if !byEmail {
+			continue
+		}
+		user, ok := users[userID]
+		if !ok || user.Email == "" {
+			continue
+		}
+		if err := s.smtpClient.SendQuizCreated(user.Email, event.Title, creatorName); err != nil {
+			log.Printf("Failed to send quiz_created email to user %s: %v", userID, err)
+		}
+	}
+
+	return nil
+}
+
+// lookupUsers fetches the profiles of the given users by ID. Users that
+// cannot be loaded are missing from the result.
+func (s *NotificationService) lookupUsers(ctx context.Context, userIDs []string) map[string]*pb.User {
+	if s.userClient == nil {
+		return nil
+	}
+
+	users, err := s.userClient.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		log.Printf("Failed to get users: %v", err)
+		return nil
+	}
+	return users
+}
+
+func (s *NotificationService) HandleQuizStarted(ctx context.Context, data []byte) error {
+	var event struct {
+		InstanceID   string   `json:"instance_id"`
+		Title        string   `json:"title"`
+		GroupID      string   `json:"group_id"`
+		CreatorID    string   `json:"creator_id"`
+		Deadline     string   `json:"deadline"`
+		Participants []string `json:"participants"`
+	}
+
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	log.Printf("Processing quiz_started event for instance %s", event.InstanceID)
+
+	// Let participants who joined early know the quiz is open
+	for _, userID := range event.Participants {
+		if inApp, _ := s.deliveryChannels(ctx, userID, (*pb.NotificationSettings).GetNewQuizzes); !inApp {
+			continue
+		}
+
 		notification := &repository.Notification{
 			UserID:  userID,
-			Type:    "quiz_created",
-			Title:   "New Quiz Available",
+			Type:    "quiz_started",
+			Title:   "Quiz Started",
 			Content: event.Title,
 			IsRead:  false,
 		}
@@ -151,11 +270,47 @@ func (s *NotificationService) HandleQuizCreated(ctx context.Context, data []byte
 	return nil
 }
 
+func (s *NotificationService) HandleQuizFinished(ctx context.Context, data []byte) error {
+	var event struct {
+		InstanceID     string   `json:"instance_id"`
+		Title          string   `json:"title"`
+		Status         string   `json:"status"`
+		CreatorID      string   `json:"creator_id"`
+		ParticipantIDs []string `json:"participant_ids"`
+	}
+
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	log.Printf("Processing quiz_finished event for instance %s", event.InstanceID)
+
+	title := "Quiz Closed"
+	if event.Status == "pending_review" {
+		title = "Quiz Closed: Answers Awaiting Review"
+	}
+
+	notification := &repository.Notification{
+		UserID:  event.CreatorID,
+		Type:    "quiz_finished",
+		Title:   title,
+		Content: fmt.Sprintf("%s (%d participants)", event.Title, len(event.ParticipantIDs)),
+		IsRead:  false,
+	}
+
+	return s.repo.CreateNotification(ctx, notification)
+}
+
 func (s *NotificationService) HandleQuizResultsReady(ctx context.Context, data []byte) error {
 	var event struct {
 		InstanceID     string   `json:"instance_id"`
 		ParticipantIDs []string `json:"participant_ids"`
 		Title          string   `json:"title"`
+		Results        []struct {
+			UserID string `json:"user_id"`
+			Score  int    `json:"score"`
+			Rank   int    `json:"rank"`
+		} `json:"results"`
 	}
 
 	if err := json.Unmarshal(data, &event); err != nil {
@@ -164,13 +319,28 @@ func (s *NotificationService) HandleQuizResultsReady(ctx context.Context, data [
 
 	log.Printf("Processing quiz_results_ready event for instance %s", event.InstanceID)
 
+	contents := make(map[string]string, len(event.Results))
+	for _, result := range event.Results {
+		contents[result.UserID] = fmt.Sprintf("%s: rank %d of %d with %d points",
+			event.Title, result.Rank, len(event.Results), result.Score)
+	}
+
 	// Create in-app notifications for participants
 	for _, userID := range event.ParticipantIDs {
+		if inApp, _ := s.deliveryChannels(ctx, userID, (*pb.NotificationSettings).GetQuizResults); !inApp {
+			continue
+		}
+
+		content, ok := contents[userID]
+		if !ok {
+			content = event.Title
+		}
+
 		notification := &repository.Notification{
 			UserID:  userID,
 			Type:    "quiz_results",
 			Title:   "Quiz Results Ready",
-			Content: event.Title,
+			Content: content,
 			IsRead:  false,
 		}
 
@@ -182,6 +352,49 @@ func (s *NotificationService) HandleQuizResultsReady(ctx context.Context, data [
 	return nil
 }
 
+func (s *NotificationService) HandleDeadlineReminder(ctx context.Context, data []byte) error {
+	var event struct {
+		InstanceID string `json:"instance_id"`
+		Title      string `json:"title"`
+		Deadline   string `json:"deadline"`
+		UserID     string `json:"user_id"`
+		Email      string `json:"email"`
+	}
+
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	log.Printf("Processing deadline_reminder event for instance %s and user %s", event.InstanceID, event.UserID)
+
+	inApp, byEmail := s.deliveryChannels(ctx, event.UserID, nil)
+
+	if inApp {
+		notification := &repository.Notification{
+			UserID:  event.UserID,
+			Type:    "deadline_reminder",
+			Title:   "Quiz Deadline Approaching",
+			Content: event.Title,
+			IsRead:  false,
+		}
+
+		if err := s.repo.CreateNotification(ctx, notification); err != nil {
+			log.Printf("Failed to create notification for user %s: %v", event.UserID, err)
+		}
+	}
+
+	if !byEmail || event.Email == "" {
+		return nil
+	}
+
+	deadline := event.Deadline
+	if t, err := time.Parse(time.RFC3339, event.Deadline); err == nil {
+		deadline = t.UTC().Format("2006-01-02 15:04 UTC")
+	}
+
+	return s.smtpClient.SendDeadlineReminder(event.Email, event.Title, deadline)
+}
+
 func (s *NotificationService) HandleSendEmail(ctx context.Context, data []byte) error {
 	var event struct {
 		To       string `json:"to"`
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "notification-service/proto"
+)
+
+type fakeUserClient struct {
+	UserClient
+	settings *pb.NotificationSettings
+	err      error
+}
+
+func (f *fakeUserClient) GetNotificationSettings(ctx context.Context, userID string) (*pb.NotificationSettings, error) {
+	return f.settings, f.err
+}
+
+func TestDeliveryChannels(t *testing.T) {
+	tests := []struct {
+		name         string
+		client       *fakeUserClient
+		enabled      func(*pb.NotificationSettings) bool
+		inApp, email bool
+	}{
+		{"both channels", &fakeUserClient{settings: &pb.NotificationSettings{NewQuizzes: true, InAppEnabled: true, EmailEnabled: true}}, (*pb.NotificationSettings).GetNewQuizzes, true, true},
+		{"email off", &fakeUserClient{settings: &pb.NotificationSettings{NewQuizzes: true, InAppEnabled: true}}, (*pb.NotificationSettings).GetNewQuizzes, true, false},
+		{"in-app off", &fakeUserClient{settings: &pb.NotificationSettings{QuizResults: true, EmailEnabled: true}}, (*pb.NotificationSettings).GetQuizResults, false, true},
+		{"kind off", &fakeUserClient{settings: &pb.NotificationSettings{InAppEnabled: true, EmailEnabled: true}}, (*pb.NotificationSettings).GetGroupInvites, false, false},
+		{"channels only", &fakeUserClient{settings: &pb.NotificationSettings{EmailEnabled: true}}, nil, false, true},
+		{"settings unavailable", &fakeUserClient{err: errors.New("unavailable")}, (*pb.NotificationSettings).GetNewQuizzes, true, true},
+	}
+
+	for _, tt := range tests {
+		s := &NotificationService{userClient: tt.client}
+		inApp, email := s.deliveryChannels(context.Background(), "u1", tt.enabled)
+		if inApp != tt.inApp || email != tt.email {
+			t.Errorf("%s: deliveryChannels = %v, %v, want %v, %v", tt.name, inApp, email, tt.inApp, tt.email)
+		}
+	}
+}
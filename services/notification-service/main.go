@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"notification-service/config"
+	"notification-service/internal/client"
 	"notification-service/internal/service"
 	"notification-service/pkg/database"
 	"notification-service/pkg/email"
@@ -51,6 +52,12 @@ func main() {
 	smtpClient := email.NewSMTPClient(&cfg.SMTP)
 	log.Println("SMTP client initialized")
 
+	userClient, err := client.NewUserClient(cfg.User.Host, cfg.User.Port)
+	if err != nil {
+		log.Fatalf("Failed to connect to User Service: %v", err)
+	}
+	defer userClient.Close()
+
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -86,7 +93,7 @@ func main() {
 		}
 	}()
 
-	notificationService := service.NewNotificationService(pgClient.GetDB(), smtpClient)
+	notificationService := service.NewNotificationService(pgClient.GetDB(), smtpClient, userClient)
 
 	grpcServer := grpc.NewServer()
 	pb.RegisterNotificationServiceServer(grpcServer, notificationService)
@@ -123,7 +130,10 @@ func startConsumers(rabbitClient *messaging.RabbitMQClient, notificationService
 	go consumeQueue(ctx, rabbitClient, "auth.send_code", notificationService.HandleSendAuthCode)
 	go consumeQueue(ctx, rabbitClient, "user.group_invites", notificationService.HandleGroupInvite)
 	go consumeQueue(ctx, rabbitClient, "quiz.created", notificationService.HandleQuizCreated)
+	go consumeQueue(ctx, rabbitClient, "quiz.started", notificationService.HandleQuizStarted)
+	go consumeQueue(ctx, rabbitClient, "quiz.finished", notificationService.HandleQuizFinished)
 	go consumeQueue(ctx, rabbitClient, "quiz.results_ready", notificationService.HandleQuizResultsReady)
+	go consumeQueue(ctx, rabbitClient, "quiz.deadline_reminder", notificationService.HandleDeadlineReminder)
 	go consumeQueue(ctx, rabbitClient, "notifications.email", notificationService.HandleSendEmail)
 	go consumeQueue(ctx, rabbitClient, "notifications.create", notificationService.HandleCreateNotification)
 
@@ -233,4 +233,50 @@ func (c *SMTPClient) SendQuizResults(email, quizTitle string) error {
 		Subject: fmt.Sprintf("Kollocol - Results for %s", quizTitle),
 		Body:    body.String(),
 	})
+}
+
+func (c *SMTPClient) SendDeadlineReminder(email, quizTitle, deadline string) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .highlight { color: #007bff; font-weight: bold; }
+        .footer { margin-top: 30px; font-size: 12px; color: #666; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h2>Kollocol - Quiz Deadline Approaching</h2>
+        <p>The quiz <span class="highlight">{{.QuizTitle}}</span> closes at <span class="highlight">{{.Deadline}}</span>.</p>
+        <p>Log in to Kollocol to finish it before the deadline!</p>
+        <div class="footer">
+            <p>This is an automated message from Kollocol.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t, err := template.New("deadline_reminder").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var body bytes.Buffer
+	data := map[string]string{
+		"QuizTitle": quizTitle,
+		"Deadline":  deadline,
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return c.SendEmail(EmailData{
+		To:      email,
+		Subject: fmt.Sprintf("Kollocol - Reminder: %s closes soon", quizTitle),
+		Body:    body.String(),
+	})
 }
\ No newline at end of file
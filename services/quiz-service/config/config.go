@@ -6,11 +6,12 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	DB       DBConfig
-	Redis    RedisConfig
-	RabbitMQ RabbitMQConfig
-	User	 UserServiceConfig
+	Server    ServerConfig
+	DB        DBConfig
+	Redis     RedisConfig
+	RabbitMQ  RabbitMQConfig
+	User      UserServiceConfig
+	Scheduler SchedulerConfig
 }
 
 type ServerConfig struct {
@@ -46,6 +47,10 @@ type UserServiceConfig struct {
 	Port string
 }
 
+type SchedulerConfig struct {
+	IntervalSec int
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -76,6 +81,9 @@ func Load() *Config {
 			Host: getEnv("USER_SERVICE_HOST", "localhost"),
 			Port: getEnv("USER_SERVICE_PORT", "50051"),
 		},
+		Scheduler: SchedulerConfig{
+			IntervalSec: getEnvAsInt("SCHEDULER_INTERVAL_SEC", 30),
+		},
 	}
 }
 
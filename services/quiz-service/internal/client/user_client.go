@@ -52,4 +52,67 @@ func (c *UserClient) CheckGroupMembership(ctx context.Context, groupID, userID s
 	}
 
 	return resp.IsMember, resp.Role, nil
-}
\ No newline at end of file
+}
+
+func (c *UserClient) GetUsersByIDs(ctx context.Context, userIDs []string) (map[string]*pb.User, error) {
+	resp, err := c.client.GetUsersByIDs(ctx, &pb.GetUsersByIDsRequest{UserIds: userIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get users: %s", resp.Message)
+	}
+
+	users := make(map[string]*pb.User, len(resp.Users))
+	for _, u := range resp.Users {
+		users[u.Id] = u
+	}
+	return users, nil
+}
+
+// GetMemberGroups returns the groups the user is a member of.
+func (c *UserClient) GetMemberGroups(ctx context.Context, userID string) ([]*pb.Group, error) {
+	resp, err := c.client.GetGroups(ctx, &pb.GetGroupsRequest{
+		UserId: userID,
+		Filter: "my",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get groups: %s", resp.Message)
+	}
+
+	return resp.Groups, nil
+}
+
+// GetNotificationSettingsByUserIDs returns the settings of the given users,
+// keyed by user id.
+func (c *UserClient) GetNotificationSettingsByUserIDs(ctx context.Context, userIDs []string) (map[string]*pb.NotificationSettings, error) {
+	resp, err := c.client.GetNotificationSettingsByUserIDs(ctx, &pb.GetNotificationSettingsByUserIDsRequest{UserIds: userIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get notification settings: %s", resp.Message)
+	}
+
+	settings := make(map[string]*pb.NotificationSettings, len(resp.Settings))
+	for _, st := range resp.Settings {
+		settings[st.UserId] = st
+	}
+	return settings, nil
+}
+
+// GetGroupMemberIDs returns the IDs of every member of the group.
+func (c *UserClient) GetGroupMemberIDs(ctx context.Context, groupID string) ([]string, error) {
+	resp, err := c.client.GetGroupMemberIDs(ctx, &pb.GetGroupMemberIDsRequest{GroupId: groupID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group members: %w", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get group members: %s", resp.Message)
+	}
+
+	return resp.MemberIds, nil
+}
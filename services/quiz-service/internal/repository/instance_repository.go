@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type InstanceRepository struct {
@@ -148,6 +149,11 @@ func (r *InstanceRepository) GetHostingInstances(ctx context.Context, userID, st
 	if err != nil {
 		return nil, err
 	}
+
+	return scanInstances(rows)
+}
+
+func scanInstances(rows *sql.Rows) ([]*Instance, error) {
 	defer rows.Close()
 
 	var instances []*Instance
@@ -176,6 +182,150 @@ func (r *InstanceRepository) GetHostingInstances(ctx context.Context, userID, st
 	return instances, rows.Err()
 }
 
+// Participation is an instance together with one participant's session.
+type Participation struct {
+	Instance      *Instance
+	SessionStatus string
+	Score         int
+	StartedAt     time.Time
+	FinishedAt    sql.NullTime
+}
+
+// ParticipationFilter narrows GetParticipatingInstances. Empty fields do not
+// filter; From and To bound the time the participant joined.
+type ParticipationFilter struct {
+	UserID  string
+	Status  string
+	GroupID string
+	From    time.Time
+	To      time.Time
+}
+
+// GetParticipatingInstances lists the instances a user joined without hosting
+// them, most recent first, and the total number matching the filter.
+func (r *InstanceRepository) GetParticipatingInstances(ctx context.Context, filter ParticipationFilter, limit, offset int) ([]*Participation, int, error) {
+	where := " WHERE s.user_id = $1 AND i.created_by <> $1"
+	args := []any{filter.UserID}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where += fmt.Sprintf(" AND i.status = $%d", len(args))
+	}
+	if filter.GroupID != "" {
+		args = append(args, filter.GroupID)
+		where += fmt.Sprintf(" AND i.group_id = $%d", len(args))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		where += fmt.Sprintf(" AND s.started_at >= $%d", len(args))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		where += fmt.Sprintf(" AND s.started_at < $%d", len(args))
+	}
+
+	from := " FROM game_sessions s JOIN quiz_instances i ON i.id = s.instance_id"
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*)"+from+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count participations: %w", err)
+	}
+
+	query := `
+		SELECT i.id, i.template_id, i.title, i.access_code, i.status, i.group_id, i.created_by, i.created_at, i.start_time, i.deadline, i.quiz_type, i.settings,
+			s.status, s.score, s.started_at, s.finished_at` + from + where +
+		fmt.Sprintf(" ORDER BY s.started_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var participations []*Participation
+	for rows.Next() {
+		p := &Participation{Instance: &Instance{}}
+		err := rows.Scan(
+			&p.Instance.ID,
+			&p.Instance.TemplateID,
+			&p.Instance.Title,
+			&p.Instance.AccessCode,
+			&p.Instance.Status,
+			&p.Instance.GroupID,
+			&p.Instance.CreatedBy,
+			&p.Instance.CreatedAt,
+			&p.Instance.StartTime,
+			&p.Instance.Deadline,
+			&p.Instance.QuizType,
+			&p.Instance.Settings,
+			&p.SessionStatus,
+			&p.Score,
+			&p.StartedAt,
+			&p.FinishedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		participations = append(participations, p)
+	}
+
+	return participations, total, rows.Err()
+}
+
+// GetGroupInstances returns the open async instances assigned to any of the
+// groups, with the user's session in each if they joined. Instances past their
+// deadline and ones the user hosts are left out.
+func (r *InstanceRepository) GetGroupInstances(ctx context.Context, groupIDs []string, userID string) ([]*Participation, error) {
+	query := `
+		SELECT i.id, i.template_id, i.title, i.access_code, i.status, i.group_id, i.created_by, i.created_at, i.start_time, i.deadline, i.quiz_type, i.settings,
+			COALESCE(s.status, ''), COALESCE(s.score, 0), COALESCE(s.started_at, i.created_at), s.finished_at
+		FROM quiz_instances i
+		LEFT JOIN game_sessions s ON s.instance_id = i.id AND s.user_id = $2
+		WHERE i.group_id = ANY($1)
+			AND i.created_by <> $2
+			AND i.quiz_type = 'async'
+			AND i.status IN ('waiting', 'active')
+			AND (i.deadline IS NULL OR i.deadline > NOW())
+		ORDER BY i.deadline ASC NULLS LAST, i.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(groupIDs), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []*Participation
+	for rows.Next() {
+		p := &Participation{Instance: &Instance{}}
+		err := rows.Scan(
+			&p.Instance.ID,
+			&p.Instance.TemplateID,
+			&p.Instance.Title,
+			&p.Instance.AccessCode,
+			&p.Instance.Status,
+			&p.Instance.GroupID,
+			&p.Instance.CreatedBy,
+			&p.Instance.CreatedAt,
+			&p.Instance.StartTime,
+			&p.Instance.Deadline,
+			&p.Instance.QuizType,
+			&p.Instance.Settings,
+			&p.SessionStatus,
+			&p.Score,
+			&p.StartedAt,
+			&p.FinishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, p)
+	}
+
+	return instances, rows.Err()
+}
+
 func (r *InstanceRepository) GetInstanceWithQuestions(ctx context.Context, instanceID string) (*InstanceWithQuestions, error) {
 	query := `
 		SELECT
@@ -316,4 +466,184 @@ func (r *InstanceRepository) GetInstanceByAccessCode(ctx context.Context, access
 	}
 
 	return instance, nil
-}
\ No newline at end of file
+}
+
+func (r *InstanceRepository) UpdateStatus(ctx context.Context, instanceID, status string) error {
+	query := `UPDATE quiz_instances SET status = $1 WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, status, instanceID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("instance not found")
+	}
+	return nil
+}
+
+// StartScheduledInstances opens the waiting async instances whose start time
+// has come. The status check in the update keeps two replicas from starting
+// the same instance.
+func (r *InstanceRepository) StartScheduledInstances(ctx context.Context, now time.Time) ([]*Instance, error) {
+	query := `
+		UPDATE quiz_instances
+		SET status = 'active'
+		WHERE status = 'waiting'
+			AND quiz_type = 'async'
+			AND start_time <= $1
+			AND (deadline IS NULL OR deadline > $1)
+		RETURNING id, template_id, title, access_code, status, group_id, created_by, created_at, start_time, deadline, quiz_type, settings
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanInstances(rows)
+}
+
+// CloseExpiredInstances closes the async instances whose deadline has passed
+// and finishes the sessions still open in them. Instances with open questions
+// go to pending_review so the creator can grade them. It returns the closed
+// instances and the sessions this call finished; sessions game-service
+// finished first are not among them.
+func (r *InstanceRepository) CloseExpiredInstances(ctx context.Context, now time.Time) ([]*Instance, []*Session, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE quiz_instances i
+		SET status = CASE WHEN EXISTS (
+				SELECT 1
+				FROM instance_questions iq
+				JOIN questions q ON q.id = iq.question_id
+				WHERE iq.instance_id = i.id AND q.type = 'open'
+			) THEN 'pending_review' ELSE 'finished' END
+		WHERE i.status IN ('waiting', 'active')
+			AND i.quiz_type = 'async'
+			AND i.deadline <= $1
+		RETURNING i.id, i.template_id, i.title, i.access_code, i.status, i.group_id, i.created_by, i.created_at, i.start_time, i.deadline, i.quiz_type, i.settings
+	`
+
+	rows, err := tx.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	instances, err := scanInstances(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(instances) == 0 {
+		return nil, nil, nil
+	}
+
+	instanceIDs := make([]string, len(instances))
+	for i, instance := range instances {
+		instanceIDs[i] = instance.ID
+	}
+
+	querySessions := `
+		UPDATE game_sessions
+		SET status = 'finished', finished_at = $2
+		WHERE instance_id = ANY($1) AND status IN ('joined', 'in_progress')
+		RETURNING instance_id, user_id, status, current_question_index, score, answers, started_at, finished_at
+	`
+	rows, err = tx.QueryContext(ctx, querySessions, pq.Array(instanceIDs), now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to finish sessions: %w", err)
+	}
+	sessions, err := scanSessions(rows)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to finish sessions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return instances, sessions, nil
+}
+
+// GetInstancesNearDeadline returns the open async group instances whose
+// deadline falls within the given window from now.
+func (r *InstanceRepository) GetInstancesNearDeadline(ctx context.Context, now time.Time, window time.Duration) ([]*Instance, error) {
+	query := `
+		SELECT id, template_id, title, access_code, status, group_id, created_by, created_at, start_time, deadline, quiz_type, settings
+		FROM quiz_instances
+		WHERE status IN ('waiting', 'active')
+			AND quiz_type = 'async'
+			AND group_id IS NOT NULL
+			AND deadline > $1
+			AND deadline <= $2
+		ORDER BY deadline ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(window))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanInstances(rows)
+}
+
+// ClaimDeadlineReminder records that the user is being reminded about the
+// instance. It reports false when a reminder was already sent, so each user
+// gets at most one per instance even with several replicas running.
+func (r *InstanceRepository) ClaimDeadlineReminder(ctx context.Context, instanceID, userID string) (bool, error) {
+	query := `
+		INSERT INTO deadline_reminders (instance_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (instance_id, user_id) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, instanceID, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// ReleaseDeadlineReminder forgets a claimed reminder that could not be sent,
+// so the next scheduler tick retries it.
+func (r *InstanceRepository) ReleaseDeadlineReminder(ctx context.Context, instanceID, userID string) error {
+	query := `DELETE FROM deadline_reminders WHERE instance_id = $1 AND user_id = $2`
+
+	_, err := r.db.ExecContext(ctx, query, instanceID, userID)
+	return err
+}
+
+// GetRemindedUserIDs returns the users already reminded about the instance's
+// deadline.
+func (r *InstanceRepository) GetRemindedUserIDs(ctx context.Context, instanceID string) ([]string, error) {
+	query := `
+		SELECT user_id
+		FROM deadline_reminders
+		WHERE instance_id = $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
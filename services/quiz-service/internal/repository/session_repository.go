@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SessionRepository works on the game_sessions table owned by game-service.
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+type Session struct {
+	InstanceID           string
+	UserID               string
+	Status               string
+	CurrentQuestionIndex int
+	Score                int
+	Answers              string // JSON array of SessionAnswer
+	StartedAt            time.Time
+	FinishedAt           sql.NullTime
+}
+
+type SessionAnswer struct {
+	QuestionID  string `json:"question_id"`
+	Answer      string `json:"answer"`
+	IsCorrect   bool   `json:"is_correct"`
+	Score       int    `json:"score"`
+	TimeSpentMs int64  `json:"time_spent_ms"`
+	NeedsReview bool   `json:"needs_review,omitempty"`
+	Feedback    string `json:"feedback,omitempty"`
+	GradedBy    string `json:"graded_by,omitempty"`
+	SubmittedAt string `json:"submitted_at,omitempty"`
+
+	Credit   float64 `json:"credit,omitempty"`
+	Strategy string  `json:"strategy,omitempty"`
+	Streak   int     `json:"streak,omitempty"`
+
+	AISuggestion *AISuggestion `json:"ai_suggestion,omitempty"`
+}
+
+// AISuggestion is the grade ml-service proposes for an open answer.
+type AISuggestion struct {
+	Similarity     float64 `json:"similarity"`
+	SuggestedScore int     `json:"suggested_score"`
+	Model          string  `json:"model"`
+}
+
+// ErrAnswerNotFound is returned by GradeAnswer when the participant has no
+// answer for the question.
+var ErrAnswerNotFound = errors.New("answer not found")
+
+func (r *SessionRepository) GetSession(ctx context.Context, instanceID, userID string) (*Session, error) {
+	query := `
+		SELECT instance_id, user_id, status, current_question_index, score, answers, started_at, finished_at
+		FROM game_sessions
+		WHERE instance_id = $1 AND user_id = $2
+	`
+
+	session := &Session{}
+	err := r.db.QueryRowContext(ctx, query, instanceID, userID).Scan(
+		&session.InstanceID,
+		&session.UserID,
+		&session.Status,
+		&session.CurrentQuestionIndex,
+		&session.Score,
+		&session.Answers,
+		&session.StartedAt,
+		&session.FinishedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *SessionRepository) GetSessionsByInstance(ctx context.Context, instanceID string) ([]*Session, error) {
+	query := `
+		SELECT instance_id, user_id, status, current_question_index, score, answers, started_at, finished_at
+		FROM game_sessions
+		WHERE instance_id = $1
+		ORDER BY score DESC, started_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSessions(rows)
+}
+
+func scanSessions(rows *sql.Rows) ([]*Session, error) {
+	defer rows.Close()
+
+	var sessions []*Session
+	for rows.Next() {
+		session := &Session{}
+		err := rows.Scan(
+			&session.InstanceID,
+			&session.UserID,
+			&session.Status,
+			&session.CurrentQuestionIndex,
+			&session.Score,
+			&session.Answers,
+			&session.StartedAt,
+			&session.FinishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// GradeAnswer stores a manual grade for one answer and recomputes the session
+// score. The session row is locked so concurrent grades are not lost.
+func (r *SessionRepository) GradeAnswer(ctx context.Context, instanceID, userID, questionID string, score, maxScore int, feedback, gradedBy string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var answersJSON string
+	err = tx.QueryRowContext(ctx,
+		`SELECT answers FROM game_sessions WHERE instance_id = $1 AND user_id = $2 FOR UPDATE`,
+		instanceID, userID,
+	).Scan(&answersJSON)
+	if err == sql.ErrNoRows {
+		return 0, ErrAnswerNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var answers []SessionAnswer
+	if err := json.Unmarshal([]byte(answersJSON), &answers); err != nil {
+		return 0, fmt.Errorf("failed to parse answers: %w", err)
+	}
+
+	found := false
+	total := 0
+	for i := range answers {
+		if answers[i].QuestionID == questionID {
+			answers[i].Score = score
+			answers[i].IsCorrect = score >= maxScore
+			answers[i].NeedsReview = false
+			answers[i].Feedback = feedback
+			answers[i].GradedBy = gradedBy
+			found = true
+		}
+		total += answers[i].Score
+	}
+	if !found {
+		return 0, ErrAnswerNotFound
+	}
+
+	updated, err := json.Marshal(answers)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE game_sessions SET answers = $1, score = $2 WHERE instance_id = $3 AND user_id = $4`,
+		string(updated), total, instanceID, userID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, tx.Commit()
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"quiz-service/internal/repository"
+	pb "quiz-service/proto"
+)
+
+// groupShare is the share of participants in each of the upper and lower
+// scoring groups used for item discrimination, as in classical test theory.
+const groupShare = 0.27
+
+const scoreBucketCount = 10
+
+func (s *QuizService) GetInstanceAnalytics(ctx context.Context, req *pb.GetInstanceAnalyticsRequest) (*pb.GetInstanceAnalyticsResponse, error) {
+	instanceWithQuestions, err := s.instanceRepo.GetInstanceWithQuestions(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	if instanceWithQuestions.Instance.CreatedBy != req.UserId {
+		return &pb.GetInstanceAnalyticsResponse{
+			HasAccess:    false,
+			ErrorMessage: "Only the quiz creator can view analytics",
+		}, nil
+	}
+
+	sessions, err := s.sessionRepo.GetSessionsByInstance(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	return &pb.GetInstanceAnalyticsResponse{
+		Analytics: buildAnalytics(instanceWithQuestions.Questions, sessions, instanceWithQuestions.Instance.CreatedBy),
+		HasAccess: true,
+	}, nil
+}
+
+type analyticsParticipant struct {
+	userID      string
+	score       int
+	totalTimeMs int64
+	answers     map[string]repository.SessionAnswer
+}
+
+func buildAnalytics(questions []*repository.Question, sessions []*repository.Session, createdBy string) *pb.InstanceAnalytics {
+	var participants []*analyticsParticipant
+	for _, session := range sessions {
+		if session.UserID == createdBy || session.Status == "kicked" {
+			continue
+		}
+
+		var answers []repository.SessionAnswer
+		if err := json.Unmarshal([]byte(session.Answers), &answers); err != nil {
+			log.Printf("Failed to parse answers of user %s: %v", session.UserID, err)
+		}
+
+		p := &analyticsParticipant{
+			userID:  session.UserID,
+			score:   session.Score,
+			answers: make(map[string]repository.SessionAnswer, len(answers)),
+		}
+		for _, a := range answers {
+			p.answers[a.QuestionID] = a
+			p.totalTimeMs += a.TimeSpentMs
+		}
+		participants = append(participants, p)
+	}
+
+	slices.SortFunc(participants, func(a, b *analyticsParticipant) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.userID, b.userID))
+	})
+	upper, lower := scoreGroups(participants)
+
+	maxScore := 0
+	for _, q := range questions {
+		maxScore += q.MaxScore
+	}
+
+	scores := make([]float64, len(participants))
+	times := make([]float64, len(participants))
+	for i, p := range participants {
+		scores[i] = float64(p.score)
+		times[i] = float64(p.totalTimeMs)
+	}
+
+	analytics := &pb.InstanceAnalytics{
+		Participants:      int32(len(participants)),
+		MaxScore:          int32(maxScore),
+		MeanScore:         round2(mean(scores)),
+		MedianScore:       round2(median(scores)),
+		MeanTimeMs:        round2(mean(times)),
+		MedianTimeMs:      round2(median(times)),
+		ScoreDistribution: scoreDistribution(scores, maxScore),
+	}
+	for _, q := range questions {
+		analytics.Questions = append(analytics.Questions, questionAnalytics(q, participants, upper, lower))
+	}
+	return analytics
+}
+
+// scoreGroups splits participants sorted by score into the upper and lower
+// groups. With fewer than two participants there is nothing to compare.
+func scoreGroups(sorted []*analyticsParticipant) (upper, lower []*analyticsParticipant) {
+	if len(sorted) < 2 {
+		return nil, nil
+	}
+	n := min(max(int(math.Round(float64(len(sorted))*groupShare)), 1), len(sorted)/2)
+	return sorted[:n], sorted[len(sorted)-n:]
+}
+
+func questionAnalytics(q *repository.Question, participants, upper, lower []*analyticsParticipant) *pb.QuestionAnalytics {
+	result := &pb.QuestionAnalytics{
+		QuestionId:   q.ID,
+		QuestionText: q.Text,
+		QuestionType: q.Type,
+		MaxScore:     int32(q.MaxScore),
+	}
+
+	var times []float64
+	for _, p := range participants {
+		a, ok := p.answers[q.ID]
+		if !ok {
+			continue
+		}
+		result.Answered++
+		times = append(times, float64(a.TimeSpentMs))
+		if a.NeedsReview {
+			result.PendingReview++
+		}
+	}
+	result.MeanTimeMs = round2(mean(times))
+
+	difficulty, _ := pValue(q, participants)
+	result.Difficulty = round2(difficulty)
+
+	upperP, upperOK := pValue(q, upper)
+	lowerP, lowerOK := pValue(q, lower)
+	if upperOK && lowerOK {
+		result.Discrimination = round2(upperP - lowerP)
+	}
+
+	result.Options = optionAnalytics(q, participants, upper, lower)
+	return result
+}
+
+// pValue is the mean credit the group earned on the question. Answers waiting
+// for review are left out; a missing answer earns nothing.
+func pValue(q *repository.Question, group []*analyticsParticipant) (float64, bool) {
+	total, graded := 0.0, 0
+	for _, p := range group {
+		a, ok := p.answers[q.ID]
+		if ok && a.NeedsReview {
+			continue
+		}
+		graded++
+		if ok {
+			total += answerCredit(a, q.MaxScore)
+		}
+	}
+	if graded == 0 {
+		return 0, false
+	}
+	return total / float64(graded), true
+}
+
+// answerCredit is the share of the question an answer earned. Manual grades
+// only store a score, which may not carry bonuses.
+func answerCredit(a repository.SessionAnswer, maxScore int) float64 {
+	switch {
+	case a.IsCorrect:
+		return 1
+	case a.GradedBy != "" && maxScore > 0:
+		return min(float64(a.Score)/float64(maxScore), 1)
+	}
+	return a.Credit
+}
+
+func optionAnalytics(q *repository.Question, participants, upper, lower []*analyticsParticipant) []*pb.OptionAnalytics {
+	var options []string
+	switch q.Type {
+	case QuestionTypeMultipleChoice, QuestionTypeMultiSelect:
+		json.Unmarshal([]byte(q.Options), &options)
+	case QuestionTypeTrueFalse:
+		options = []string{"true", "false"}
+	default:
+		return nil
+	}
+
+	correct := pickedOptions(q.Type, options, correctOptionAnswer(q))
+	counts := optionCounts(q, options, participants)
+	upperCounts := optionCounts(q, options, upper)
+	lowerCounts := optionCounts(q, options, lower)
+
+	result := make([]*pb.OptionAnalytics, len(options))
+	for i, text := range options {
+		result[i] = &pb.OptionAnalytics{
+			Index:      int32(i),
+			Text:       text,
+			IsCorrect:  slices.Contains(correct, i),
+			Count:      counts[i],
+			UpperCount: upperCounts[i],
+			LowerCount: lowerCounts[i],
+		}
+	}
+	return result
+}
+
+func optionCounts(q *repository.Question, options []string, group []*analyticsParticipant) []int32 {
+	counts := make([]int32, len(options))
+	for _, p := range group {
+		if a, ok := p.answers[q.ID]; ok {
+			for _, idx := range pickedOptions(q.Type, options, a.Answer) {
+				counts[idx]++
+			}
+		}
+	}
+	return counts
+}
+
+// correctOptionAnswer returns the stored correct answer in the same form as a
+// participant's answer.
+func correctOptionAnswer(q *repository.Question) string {
+	var text string
+	if err := json.Unmarshal([]byte(q.CorrectAnswer), &text); err == nil {
+		return text
+	}
+	return q.CorrectAnswer
+}
+
+// pickedOptions returns the indices of the options an answer selects. A
+// multiple choice answer may be an index or the option text.
+func pickedOptions(questionType string, options []string, answer string) []int {
+	answer = strings.TrimSpace(answer)
+
+	switch questionType {
+	case QuestionTypeMultiSelect:
+		var indices []int
+		if err := json.Unmarshal([]byte(answer), &indices); err != nil {
+			indices = nil
+			for _, part := range strings.Split(answer, ",") {
+				idx, err := strconv.Atoi(strings.TrimSpace(part))
+				if err != nil {
+					return nil
+				}
+				indices = append(indices, idx)
+			}
+		}
+		var picked []int
+		for _, idx := range indices {
+			if idx >= 0 && idx < len(options) && !slices.Contains(picked, idx) {
+				picked = append(picked, idx)
+			}
+		}
+		return picked
+
+	case QuestionTypeTrueFalse:
+		value, err := strconv.ParseBool(strings.ToLower(answer))
+		if err != nil {
+			return nil
+		}
+		if value {
+			return []int{0}
+		}
+		return []int{1}
+	}
+
+	if idx, err := strconv.Atoi(answer); err == nil {
+		if idx >= 0 && idx < len(options) {
+			return []int{idx}
+		}
+		return nil
+	}
+	for i, option := range options {
+		if strings.EqualFold(strings.TrimSpace(option), answer) {
+			return []int{i}
+		}
+	}
+	return nil
+}
+
+// scoreDistribution counts participants in buckets of equal width by the
+// share of the maximum score they reached. Bonuses can push a score past the
+// maximum; it then counts in the top bucket.
+func scoreDistribution(scores []float64, maxScore int) []*pb.ScoreBucket {
+	width := 100.0 / scoreBucketCount
+	buckets := make([]*pb.ScoreBucket, scoreBucketCount)
+	for i := range buckets {
+		buckets[i] = &pb.ScoreBucket{
+			MinPercent: float64(i) * width,
+			MaxPercent: float64(i+1) * width,
+		}
+	}
+
+	for _, score := range scores {
+		percent := 0.0
+		if maxScore > 0 {
+			percent = score / float64(maxScore) * 100
+		}
+		idx := min(max(int(percent/width), 0), scoreBucketCount-1)
+		buckets[idx].Count++
+	}
+	return buckets
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
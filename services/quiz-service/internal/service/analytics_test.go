@@ -0,0 +1,90 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"quiz-service/internal/repository"
+)
+
+func analyticsSession(userID string, score int, answers ...repository.SessionAnswer) *repository.Session {
+	data, _ := json.Marshal(answers)
+	return &repository.Session{UserID: userID, Status: "finished", Score: score, Answers: string(data)}
+}
+
+func TestBuildAnalytics(t *testing.T) {
+	questions := []*repository.Question{
+		{ID: "q1", Type: "multiple_choice", Options: `["A","B","C"]`, CorrectAnswer: `"0"`, MaxScore: 10},
+		{ID: "q2", Type: "open", MaxScore: 10},
+	}
+	sessions := []*repository.Session{
+		analyticsSession("host", 0),
+		analyticsSession("top", 20,
+			repository.SessionAnswer{QuestionID: "q1", Answer: "0", IsCorrect: true, TimeSpentMs: 1000},
+			repository.SessionAnswer{QuestionID: "q2", Answer: "essay", Score: 10, GradedBy: "host", TimeSpentMs: 3000},
+		),
+		analyticsSession("mid", 10,
+			repository.SessionAnswer{QuestionID: "q1", Answer: "A", IsCorrect: true, TimeSpentMs: 2000},
+			repository.SessionAnswer{QuestionID: "q2", Answer: "essay", NeedsReview: true, TimeSpentMs: 4000},
+		),
+		analyticsSession("low", 0,
+			repository.SessionAnswer{QuestionID: "q1", Answer: "2", TimeSpentMs: 3000},
+		),
+		{UserID: "gone", Status: "kicked", Score: 99, Answers: "[]"},
+	}
+
+	a := buildAnalytics(questions, sessions, "host")
+
+	if a.Participants != 3 || a.MaxScore != 20 || a.MeanScore != 10 || a.MedianScore != 10 {
+		t.Fatalf("summary = %+v", a)
+	}
+	if a.MeanTimeMs != 4333.33 || a.MedianTimeMs != 4000 {
+		t.Fatalf("times = %v / %v", a.MeanTimeMs, a.MedianTimeMs)
+	}
+	if a.ScoreDistribution[0].Count != 1 || a.ScoreDistribution[5].Count != 1 || a.ScoreDistribution[9].Count != 1 {
+		t.Fatalf("distribution = %v", a.ScoreDistribution)
+	}
+
+	q1 := a.Questions[0]
+	if q1.Answered != 3 || q1.Difficulty != 0.67 || q1.Discrimination != 1 {
+		t.Fatalf("q1 = %+v", q1)
+	}
+	if len(q1.Options) != 3 || !q1.Options[0].IsCorrect || q1.Options[0].Count != 2 || q1.Options[2].Count != 1 {
+		t.Fatalf("q1 options = %v", q1.Options)
+	}
+	if q1.Options[0].UpperCount != 1 || q1.Options[2].LowerCount != 1 || q1.Options[2].UpperCount != 0 {
+		t.Fatalf("q1 distractors = %v", q1.Options)
+	}
+
+	q2 := a.Questions[1]
+	if q2.Answered != 2 || q2.PendingReview != 1 || q2.Difficulty != 0.5 || q2.Options != nil {
+		t.Fatalf("q2 = %+v", q2)
+	}
+}
+
+func TestPickedOptions(t *testing.T) {
+	options := []string{"A", "B", "C"}
+	tests := []struct {
+		questionType string
+		answer       string
+		want         []int
+	}{
+		{"multiple_choice", "1", []int{1}},
+		{"multiple_choice", " c ", []int{2}},
+		{"multiple_choice", "5", nil},
+		{"multi_select", "[2,0,2]", []int{2, 0}},
+		{"multi_select", "0, 1", []int{0, 1}},
+		{"true_false", "False", []int{1}},
+	}
+	for _, tt := range tests {
+		got := pickedOptions(tt.questionType, options, tt.answer)
+		if len(got) != len(tt.want) {
+			t.Fatalf("pickedOptions(%s, %q) = %v, want %v", tt.questionType, tt.answer, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("pickedOptions(%s, %q) = %v, want %v", tt.questionType, tt.answer, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"quiz-service/internal/repository"
+	pb "quiz-service/proto"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultParticipationLimit = 20
+	maxParticipationLimit     = 100
+)
+
+func (s *QuizService) GetParticipatingInstances(ctx context.Context, req *pb.GetParticipatingInstancesRequest) (*pb.GetParticipatingInstancesResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultParticipationLimit
+	}
+	limit = min(limit, maxParticipationLimit)
+	offset := max(req.Offset, 0)
+
+	filter := repository.ParticipationFilter{
+		UserID:  req.UserId,
+		Status:  req.Status,
+		GroupID: req.GroupId,
+	}
+	if req.From != nil {
+		filter.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		filter.To = req.To.AsTime()
+	}
+
+	participations, total, err := s.instanceRepo.GetParticipatingInstances(ctx, filter, int(limit), int(offset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participating instances: %w", err)
+	}
+
+	protoParticipations := []*pb.Participation{}
+	for _, p := range participations {
+		protoParticipations = append(protoParticipations,
+			s.participationToProto(p.Instance, p.SessionStatus, p.Score, p.StartedAt, p.FinishedAt))
+	}
+
+	return &pb.GetParticipatingInstancesResponse{
+		Participations: protoParticipations,
+		Total:          int32(total),
+	}, nil
+}
+
+// GetParticipantResult summarizes how a participant did in one instance and
+// where they placed among the others.
+func (s *QuizService) GetParticipantResult(ctx context.Context, req *pb.GetParticipantResultRequest) (*pb.GetParticipantResultResponse, error) {
+	instanceWithQuestions, err := s.instanceRepo.GetInstanceWithQuestions(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	instance := instanceWithQuestions.Instance
+	questions := instanceWithQuestions.Questions
+
+	sessions, err := s.sessionRepo.GetSessionsByInstance(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	var session *repository.Session
+	for _, candidate := range sessions {
+		if candidate.UserID == req.UserId {
+			session = candidate
+		}
+	}
+	if session == nil || instance.CreatedBy == req.UserId {
+		return &pb.GetParticipantResultResponse{
+			HasAccess:    false,
+			ErrorMessage: "You did not participate in this quiz",
+		}, nil
+	}
+
+	resp := &pb.GetParticipantResultResponse{
+		Participation:  s.participationToProto(instance, session.Status, session.Score, session.StartedAt, session.FinishedAt),
+		TotalQuestions: int32(len(questions)),
+		HasAccess:      true,
+	}
+	for _, q := range questions {
+		resp.MaxScore += int32(q.MaxScore)
+	}
+
+	var answers []repository.SessionAnswer
+	if err := json.Unmarshal([]byte(session.Answers), &answers); err != nil {
+		log.Printf("Failed to parse answers of user %s: %v", session.UserID, err)
+	}
+	for _, a := range answers {
+		resp.Answered++
+		if a.IsCorrect {
+			resp.Correct++
+		}
+		if a.NeedsReview {
+			resp.PendingReview++
+		}
+	}
+
+	results, totalTimes := participantResults(questions, sessions, instance.CreatedBy)
+	rankResults(results, totalTimes, resp.Participation.Instance.Settings.GetLeaderboardRanking())
+	resp.Participants = int32(len(results))
+	for _, result := range results {
+		if result.UserId == req.UserId {
+			resp.Rank = result.Rank
+		}
+	}
+
+	resp.Final = (instance.Status == "finished" || instance.Status == "reviewed") && resp.PendingReview == 0
+
+	return resp, nil
+}
+
+func (s *QuizService) participationToProto(instance *repository.Instance, status string, score int, startedAt time.Time, finishedAt sql.NullTime) *pb.Participation {
+	participation := &pb.Participation{
+		Instance:      s.instanceToProto(instance),
+		SessionStatus: status,
+		Score:         int32(score),
+		StartedAt:     timestamppb.New(startedAt),
+	}
+	if finishedAt.Valid {
+		participation.FinishedAt = timestamppb.New(finishedAt.Time)
+	}
+	return participation
+}
+
+// GetAvailableInstances lists the async quizzes assigned to the user's groups
+// that can still be taken, so members do not need the access code.
+func (s *QuizService) GetAvailableInstances(ctx context.Context, req *pb.GetAvailableInstancesRequest) (*pb.GetAvailableInstancesResponse, error) {
+	groups, err := s.userClient.GetMemberGroups(ctx, req.UserId)
+	if err != nil {
+		log.Printf("Failed to get groups of user %s: %v", req.UserId, err)
+		return &pb.GetAvailableInstancesResponse{
+			Success: false,
+			Message: "Failed to get your groups",
+		}, nil
+	}
+
+	resp := &pb.GetAvailableInstancesResponse{
+		Instances: []*pb.AvailableInstance{},
+		Success:   true,
+	}
+	if len(groups) == 0 {
+		return resp, nil
+	}
+
+	groupNames := make(map[string]string, len(groups))
+	groupIDs := make([]string, len(groups))
+	for i, group := range groups {
+		groupIDs[i] = group.Id
+		groupNames[group.Id] = group.Name
+	}
+
+	instances, err := s.instanceRepo.GetGroupInstances(ctx, groupIDs, req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group instances: %w", err)
+	}
+
+	for _, p := range instances {
+		resp.Instances = append(resp.Instances, &pb.AvailableInstance{
+			Instance:      s.instanceToProto(p.Instance),
+			GroupName:     groupNames[p.Instance.GroupID.String],
+			SessionStatus: p.SessionStatus,
+			Score:         int32(p.Score),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"quiz-service/internal/repository"
+	pb "quiz-service/proto"
+)
+
+const (
+	QuestionTypeOpen           = "open"
+	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeMultiSelect    = "multi_select"
+	QuestionTypeOrdering       = "ordering"
+	QuestionTypeNumeric        = "numeric"
+	QuestionTypeTrueFalse      = "true_false"
+	QuestionTypeMatching       = "matching"
+)
+
+// NumericAnswer is the correct answer of a numeric question. Any answer
+// within Tolerance of Value is accepted.
+type NumericAnswer struct {
+	Value     float64 `json:"value"`
+	Tolerance float64 `json:"tolerance"`
+}
+
+// correctAnswerJSON validates the correct answer against the question type and
+// returns it as stored in questions.correct_answer:
+//
+//	open, multiple_choice  the answer text or option index, as a JSON string
+//	multi_select           JSON array of correct option indices, e.g. [0,2]
+//	ordering               JSON array of option indices in the correct order
+//	numeric                {"value": 9.8, "tolerance": 0.1}, or a plain number
+//	true_false             true or false
+//	matching               JSON array of right-hand items; item i matches options[i]
+func correctAnswerJSON(q *pb.QuestionInput) (string, error) {
+	raw := strings.TrimSpace(q.CorrectAnswer)
+	if raw == "" {
+		return "", fmt.Errorf("correct answer is required")
+	}
+
+	switch q.Type {
+	case QuestionTypeOpen:
+		return repository.CorrectAnswerToJSON(q.CorrectAnswer)
+
+	case QuestionTypeMultipleChoice:
+		if len(q.Options) < 2 {
+			return "", fmt.Errorf("multiple choice question needs at least 2 options")
+		}
+		// Option text wins over an index, so numeric options like "20" can be
+		// named as they are written.
+		if slices.Contains(q.Options, raw) {
+			return repository.CorrectAnswerToJSON(q.CorrectAnswer)
+		}
+		idx, err := strconv.Atoi(raw)
+		if err != nil {
+			return "", fmt.Errorf("correct answer must be an option index or one of the options")
+		}
+		if idx < 0 || idx >= len(q.Options) {
+			return "", fmt.Errorf("correct option %d is out of range", idx)
+		}
+		return repository.CorrectAnswerToJSON(q.CorrectAnswer)
+
+	case QuestionTypeMultiSelect:
+		indices, err := parseOptionIndices(raw, len(q.Options))
+		if err != nil {
+			return "", err
+		}
+		if len(indices) == 0 {
+			return "", fmt.Errorf("multi select question needs at least one correct option")
+		}
+		slices.Sort(indices)
+		return marshalCorrectAnswer(indices)
+
+	case QuestionTypeOrdering:
+		indices, err := parseOptionIndices(raw, len(q.Options))
+		if err != nil {
+			return "", err
+		}
+		if len(q.Options) < 2 || len(indices) != len(q.Options) {
+			return "", fmt.Errorf("ordering answer must list all %d options", len(q.Options))
+		}
+		return marshalCorrectAnswer(indices)
+
+	case QuestionTypeNumeric:
+		var answer NumericAnswer
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			answer.Value = value
+		} else if err := json.Unmarshal([]byte(raw), &answer); err != nil {
+			return "", fmt.Errorf("numeric answer must be a number or {\"value\", \"tolerance\"}")
+		}
+		if math.IsNaN(answer.Value) || math.IsInf(answer.Value, 0) || answer.Tolerance < 0 {
+			return "", fmt.Errorf("numeric answer must be finite with a non-negative tolerance")
+		}
+		return marshalCorrectAnswer(answer)
+
+	case QuestionTypeTrueFalse:
+		value, err := strconv.ParseBool(strings.ToLower(raw))
+		if err != nil {
+			return "", fmt.Errorf("true/false answer must be true or false")
+		}
+		return marshalCorrectAnswer(value)
+
+	case QuestionTypeMatching:
+		var pairs []string
+		if err := json.Unmarshal([]byte(raw), &pairs); err != nil {
+			return "", fmt.Errorf("matching answer must be a JSON array of strings")
+		}
+		if len(q.Options) < 2 || len(pairs) != len(q.Options) {
+			return "", fmt.Errorf("matching answer must have one item per option")
+		}
+		for _, p := range pairs {
+			if strings.TrimSpace(p) == "" {
+				return "", fmt.Errorf("matching items must not be empty")
+			}
+		}
+		return marshalCorrectAnswer(pairs)
+	}
+
+	return "", fmt.Errorf("unknown question type: %s", q.Type)
+}
+
+func parseOptionIndices(raw string, optionCount int) ([]int, error) {
+	var indices []int
+	if err := json.Unmarshal([]byte(raw), &indices); err != nil {
+		return nil, fmt.Errorf("correct answer must be a JSON array of option indices")
+	}
+
+	seen := make(map[int]bool, len(indices))
+	for _, idx := range indices {
+		if idx < 0 || idx >= optionCount {
+			return nil, fmt.Errorf("correct option %d is out of range", idx)
+		}
+		if seen[idx] {
+			return nil, fmt.Errorf("correct option %d is listed twice", idx)
+		}
+		seen[idx] = true
+	}
+	return indices, nil
+}
+
+func marshalCorrectAnswer(answer any) (string, error) {
+	data, err := json.Marshal(answer)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// validateQuestions checks every question before anything is written, so an
+// invalid template is rejected as a whole.
+func validateQuestions(questions []*pb.QuestionInput) ([]string, error) {
+	correctAnswers := make([]string, len(questions))
+	for i, q := range questions {
+		correct, err := correctAnswerJSON(q)
+		if err != nil {
+			return nil, fmt.Errorf("question %d: %w", i+1, err)
+		}
+		correctAnswers[i] = correct
+	}
+	return correctAnswers, nil
+}
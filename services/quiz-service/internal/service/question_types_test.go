@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+
+	pb "quiz-service/proto"
+)
+
+func TestCorrectAnswerJSON(t *testing.T) {
+	options := []string{"a", "b", "c"}
+	tests := []struct {
+		question *pb.QuestionInput
+		want     string
+		wantErr  bool
+	}{
+		{&pb.QuestionInput{Type: "open", CorrectAnswer: "Paris"}, `"Paris"`, false},
+		{&pb.QuestionInput{Type: "multiple_choice", Options: options, CorrectAnswer: "1"}, `"1"`, false},
+		{&pb.QuestionInput{Type: "multiple_choice", Options: options, CorrectAnswer: "d"}, "", true},
+		{&pb.QuestionInput{Type: "multiple_choice", Options: []string{"10", "20", "30"}, CorrectAnswer: "20"}, `"20"`, false},
+		{&pb.QuestionInput{Type: "multiple_choice", Options: []string{"10", "20", "30"}, CorrectAnswer: "5"}, "", true},
+		{&pb.QuestionInput{Type: "multi_select", Options: options, CorrectAnswer: "[2, 0]"}, `[0,2]`, false},
+		{&pb.QuestionInput{Type: "multi_select", Options: options, CorrectAnswer: "[3]"}, "", true},
+		{&pb.QuestionInput{Type: "ordering", Options: options, CorrectAnswer: "[2,0,1]"}, `[2,0,1]`, false},
+		{&pb.QuestionInput{Type: "ordering", Options: options, CorrectAnswer: "[2,0]"}, "", true},
+		{&pb.QuestionInput{Type: "numeric", CorrectAnswer: "9.8"}, `{"value":9.8,"tolerance":0}`, false},
+		{&pb.QuestionInput{Type: "numeric", CorrectAnswer: `{"value":10,"tolerance":-1}`}, "", true},
+		{&pb.QuestionInput{Type: "true_false", CorrectAnswer: "True"}, `true`, false},
+		{&pb.QuestionInput{Type: "true_false", CorrectAnswer: "yes"}, "", true},
+		{&pb.QuestionInput{Type: "matching", Options: []string{"x", "y"}, CorrectAnswer: `["1","2"]`}, `["1","2"]`, false},
+		{&pb.QuestionInput{Type: "matching", Options: []string{"x", "y"}, CorrectAnswer: `["1"]`}, "", true},
+		{&pb.QuestionInput{Type: "essay", CorrectAnswer: "x"}, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := correctAnswerJSON(tt.question)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s %q: error = %v, wantErr %v", tt.question.Type, tt.question.CorrectAnswer, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s %q: got %s, want %s", tt.question.Type, tt.question.CorrectAnswer, got, tt.want)
+		}
+	}
+}
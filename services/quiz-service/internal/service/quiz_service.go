@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -20,12 +21,17 @@ type RabbitMQPublisher interface {
 
 type UserClient interface {
 	CheckGroupMembership(ctx context.Context, groupID, userID string) (bool, string, error)
+	GetUsersByIDs(ctx context.Context, userIDs []string) (map[string]*pb.User, error)
+	GetMemberGroups(ctx context.Context, userID string) ([]*pb.Group, error)
+	GetGroupMemberIDs(ctx context.Context, groupID string) ([]string, error)
+	GetNotificationSettingsByUserIDs(ctx context.Context, userIDs []string) (map[string]*pb.NotificationSettings, error)
 }
 
 type QuizService struct {
 	pb.UnimplementedQuizServiceServer
 	templateRepo *repository.TemplateRepository
 	instanceRepo *repository.InstanceRepository
+	sessionRepo  *repository.SessionRepository
 	mqPublisher  RabbitMQPublisher
 	userClient   UserClient
 }
@@ -38,12 +44,18 @@ func NewQuizService(
 	return &QuizService{
 		templateRepo: repository.NewTemplateRepository(db),
 		instanceRepo: repository.NewInstanceRepository(db),
+		sessionRepo:  repository.NewSessionRepository(db),
 		mqPublisher:  mqPublisher,
 		userClient:   userClient,
 	}
 }
 
 func (s *QuizService) CreateTemplate(ctx context.Context, req *pb.CreateTemplateRequest) (*pb.CreateTemplateResponse, error) {
+	correctAnswers, err := validateQuestions(req.Questions)
+	if err != nil {
+		return &pb.CreateTemplateResponse{ErrorMessage: err.Error()}, nil
+	}
+
 	settingsJSON, err := json.Marshal(req.Settings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal settings: %w", err)
@@ -62,16 +74,13 @@ func (s *QuizService) CreateTemplate(ctx context.Context, req *pb.CreateTemplate
 	}
 
 	var questions []*repository.Question
-	for _, q := range req.Questions {
+	for i, q := range req.Questions {
 		optionsJSON, err := json.Marshal(q.Options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal options: %w", err)
 		}
 
-		correctAnswerJSON, err := repository.CorrectAnswerToJSON(q.CorrectAnswer)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal correct_answer: %w", err)
-		}
+		correctAnswerJSON := correctAnswers[i]
 
 		question := &repository.Question{
 			TemplateID:    template.ID,
@@ -154,6 +163,11 @@ func (s *QuizService) UpdateTemplate(ctx context.Context, req *pb.UpdateTemplate
 		return nil, fmt.Errorf("unauthorized: user is not the owner")
 	}
 
+	correctAnswers, err := validateQuestions(req.Questions)
+	if err != nil {
+		return &pb.UpdateTemplateResponse{ErrorMessage: err.Error()}, nil
+	}
+
 	settingsJSON, err := json.Marshal(req.Settings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal settings: %w", err)
@@ -186,16 +200,13 @@ func (s *QuizService) UpdateTemplate(ctx context.Context, req *pb.UpdateTemplate
 	}
 
 	var questions []*repository.Question
-	for _, q := range req.Questions {
+	for i, q := range req.Questions {
 		optionsJSON, err := json.Marshal(q.Options)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal options: %w", err)
 		}
 
-		correctAnswerJSON, err := repository.CorrectAnswerToJSON(q.CorrectAnswer)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal correct_answer: %w", err)
-		}
+		correctAnswerJSON := correctAnswers[i]
 
 		var questionIDToLink string
 		var question *repository.Question
@@ -287,10 +298,21 @@ func (s *QuizService) CreateInstance(ctx context.Context, req *pb.CreateInstance
 		instance.GroupID = sql.NullString{String: req.GroupId, Valid: true}
 	}
 
+	if req.StartTime != nil {
+		if template.QuizType != "async" {
+			return &pb.CreateInstanceResponse{ErrorMessage: "start_time is only supported for async quizzes"}, nil
+		}
+		instance.StartTime = sql.NullTime{Time: req.StartTime.AsTime(), Valid: true}
+	}
+
 	if req.Deadline != nil {
 		instance.Deadline = sql.NullTime{Time: req.Deadline.AsTime(), Valid: true}
 	}
 
+	if instance.StartTime.Valid && instance.Deadline.Valid && !instance.Deadline.Time.After(instance.StartTime.Time) {
+		return &pb.CreateInstanceResponse{ErrorMessage: "deadline must be after start_time"}, nil
+	}
+
 	if err := s.instanceRepo.CreateInstance(ctx, instance); err != nil {
 		return nil, fmt.Errorf("failed to create instance: %w", err)
 	}
@@ -360,6 +382,261 @@ func (s *QuizService) GetHostingInstances(ctx context.Context, req *pb.GetHostin
 	}, nil
 }
 
+func (s *QuizService) GetReview(ctx context.Context, req *pb.GetReviewRequest) (*pb.GetReviewResponse, error) {
+	instanceWithQuestions, err := s.instanceRepo.GetInstanceWithQuestions(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	instance := s.instanceToProto(instanceWithQuestions.Instance)
+	if !instance.Settings.AllowReview {
+		return &pb.GetReviewResponse{
+			HasAccess:    false,
+			ErrorMessage: "Review is not allowed for this quiz",
+		}, nil
+	}
+
+	session, err := s.sessionRepo.GetSession(ctx, req.InstanceId, req.UserId)
+	if err != nil {
+		return &pb.GetReviewResponse{
+			HasAccess:    false,
+			ErrorMessage: "You did not participate in this quiz",
+		}, nil
+	}
+
+	var answers []repository.SessionAnswer
+	if err := json.Unmarshal([]byte(session.Answers), &answers); err != nil {
+		return nil, fmt.Errorf("failed to parse answers: %w", err)
+	}
+
+	answersByQuestion := make(map[string]repository.SessionAnswer, len(answers))
+	for _, a := range answers {
+		answersByQuestion[a.QuestionID] = a
+	}
+
+	showCorrect := instance.Settings.ShowCorrectAnswers && isInstanceClosed(instance.Status)
+
+	var items []*pb.ReviewItem
+	for _, q := range s.questionsToProto(instanceWithQuestions.Questions) {
+		item := &pb.ReviewItem{Question: q}
+		if showCorrect {
+			item.CorrectAnswer = q.CorrectAnswer
+		}
+		q.CorrectAnswer = ""
+		q.AiAnswer = ""
+
+		if a, ok := answersByQuestion[q.Id]; ok {
+			item.Answered = true
+			item.Answer = a.Answer
+			item.IsCorrect = a.IsCorrect
+			item.Score = int32(a.Score)
+			item.TimeSpentMs = a.TimeSpentMs
+		}
+		items = append(items, item)
+	}
+
+	return &pb.GetReviewResponse{
+		Instance:              instance,
+		Items:                 items,
+		TotalScore:            int32(session.Score),
+		CorrectAnswersVisible: showCorrect,
+		HasAccess:             true,
+	}, nil
+}
+
+func (s *QuizService) GetUngradedAnswers(ctx context.Context, req *pb.GetUngradedAnswersRequest) (*pb.GetUngradedAnswersResponse, error) {
+	instanceWithQuestions, err := s.instanceRepo.GetInstanceWithQuestions(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	if instanceWithQuestions.Instance.CreatedBy != req.UserId {
+		return &pb.GetUngradedAnswersResponse{
+			HasAccess:    false,
+			ErrorMessage: "Only the quiz creator can grade answers",
+		}, nil
+	}
+
+	questions := make(map[string]*repository.Question, len(instanceWithQuestions.Questions))
+	for _, q := range instanceWithQuestions.Questions {
+		questions[q.ID] = q
+	}
+
+	sessions, err := s.sessionRepo.GetSessionsByInstance(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	answers := []*pb.UngradedAnswer{}
+	for _, session := range sessions {
+		var sessionAnswers []repository.SessionAnswer
+		if err := json.Unmarshal([]byte(session.Answers), &sessionAnswers); err != nil {
+			log.Printf("Failed to parse answers of user %s: %v", session.UserID, err)
+			continue
+		}
+
+		for _, a := range sessionAnswers {
+			if !a.NeedsReview {
+				continue
+			}
+			answer := &pb.UngradedAnswer{
+				UserId:      session.UserID,
+				QuestionId:  a.QuestionID,
+				Answer:      a.Answer,
+				SubmittedAt: a.SubmittedAt,
+			}
+			if q, ok := questions[a.QuestionID]; ok {
+				answer.QuestionText = q.Text
+				answer.MaxScore = int32(q.MaxScore)
+				answer.CorrectAnswer = q.CorrectAnswer
+			}
+			if a.AISuggestion != nil {
+				answer.AiSuggestion = &pb.AiSuggestion{
+					Similarity:     a.AISuggestion.Similarity,
+					SuggestedScore: int32(a.AISuggestion.SuggestedScore),
+					Model:          a.AISuggestion.Model,
+				}
+			}
+			answers = append(answers, answer)
+		}
+	}
+
+	return &pb.GetUngradedAnswersResponse{
+		Answers:   answers,
+		HasAccess: true,
+	}, nil
+}
+
+func (s *QuizService) GradeAnswer(ctx context.Context, req *pb.GradeAnswerRequest) (*pb.GradeAnswerResponse, error) {
+	instanceWithQuestions, err := s.instanceRepo.GetInstanceWithQuestions(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	instance := instanceWithQuestions.Instance
+	if instance.CreatedBy != req.HostUserId {
+		return &pb.GradeAnswerResponse{
+			Success: false,
+			Message: "Only the quiz creator can grade answers",
+		}, nil
+	}
+
+	if instance.Status != "pending_review" {
+		return &pb.GradeAnswerResponse{
+			Success: false,
+			Message: "Quiz is not awaiting review",
+		}, nil
+	}
+
+	var question *repository.Question
+	for _, q := range instanceWithQuestions.Questions {
+		if q.ID == req.QuestionId {
+			question = q
+			break
+		}
+	}
+	if question == nil {
+		return &pb.GradeAnswerResponse{
+			Success: false,
+			Message: "Question not found",
+		}, nil
+	}
+
+	if req.Score < 0 || int(req.Score) > question.MaxScore {
+		return &pb.GradeAnswerResponse{
+			Success: false,
+			Message: fmt.Sprintf("Score must be between 0 and %d", question.MaxScore),
+		}, nil
+	}
+
+	total, err := s.sessionRepo.GradeAnswer(ctx, req.InstanceId, req.UserId, req.QuestionId, int(req.Score), question.MaxScore, req.Feedback, req.HostUserId)
+	if errors.Is(err, repository.ErrAnswerNotFound) {
+		return &pb.GradeAnswerResponse{
+			Success: false,
+			Message: "Answer not found",
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to grade answer: %w", err)
+	}
+
+	return &pb.GradeAnswerResponse{
+		Success:    true,
+		Message:    "Answer graded successfully",
+		TotalScore: int32(total),
+	}, nil
+}
+
+func (s *QuizService) PublishResults(ctx context.Context, req *pb.PublishResultsRequest) (*pb.PublishResultsResponse, error) {
+	instance, err := s.instanceRepo.GetInstanceByID(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	if instance.CreatedBy != req.UserId {
+		return &pb.PublishResultsResponse{
+			Success: false,
+			Message: "Only the quiz creator can publish results",
+		}, nil
+	}
+
+	if instance.Status != "pending_review" {
+		return &pb.PublishResultsResponse{
+			Success: false,
+			Message: "Quiz is not awaiting review",
+		}, nil
+	}
+
+	sessions, err := s.sessionRepo.GetSessionsByInstance(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	ungraded := 0
+	for _, session := range sessions {
+		if session.UserID == instance.CreatedBy || session.Status == "kicked" {
+			continue
+		}
+
+		var answers []repository.SessionAnswer
+		if err := json.Unmarshal([]byte(session.Answers), &answers); err != nil {
+			log.Printf("Failed to parse answers of user %s: %v", session.UserID, err)
+			continue
+		}
+		for _, a := range answers {
+			if a.NeedsReview {
+				ungraded++
+			}
+		}
+	}
+
+	if ungraded > 0 {
+		return &pb.PublishResultsResponse{
+			Success: false,
+			Message: fmt.Sprintf("%d answers are still ungraded", ungraded),
+		}, nil
+	}
+
+	if err := s.instanceRepo.UpdateStatus(ctx, req.InstanceId, "reviewed"); err != nil {
+		return nil, fmt.Errorf("failed to update instance status: %w", err)
+	}
+
+	s.publishResultsReady(ctx, instance, sessions)
+
+	return &pb.PublishResultsResponse{
+		Success: true,
+		Message: "Results published successfully",
+	}, nil
+}
+
+func isInstanceClosed(status string) bool {
+	switch status {
+	case "finished", "pending_review", "reviewed":
+		return true
+	}
+	return false
+}
+
 func (s *QuizService) templateToProto(t *repository.Template) *pb.QuizTemplate {
 	var settings pb.QuizSettings
 	json.Unmarshal([]byte(t.Settings), &settings)
@@ -441,9 +718,10 @@ func (s *QuizService) publishAIAnswerRequest(ctx context.Context, templateID str
 	}
 
 	type QuestionData struct {
-		QuestionID string `json:"question_id"`
-		Text       string `json:"text"`
-		Type       string `json:"type"`
+		QuestionID string          `json:"question_id"`
+		Text       string          `json:"text"`
+		Type       string          `json:"type"`
+		Options    json.RawMessage `json:"options,omitempty"`
 	}
 
 	type AIAnswerRequestEvent struct {
@@ -454,10 +732,15 @@ func (s *QuizService) publishAIAnswerRequest(ctx context.Context, templateID str
 
 	var questionData []QuestionData
 	for _, q := range questions {
+		var options json.RawMessage
+		if q.Options != "" && json.Valid([]byte(q.Options)) {
+			options = json.RawMessage(q.Options)
+		}
 		questionData = append(questionData, QuestionData{
 			QuestionID: q.ID,
 			Text:       q.Text,
 			Type:       q.Type,
+			Options:    options,
 		})
 	}
 
@@ -484,21 +767,33 @@ func (s *QuizService) publishQuizCreated(ctx context.Context, instance *reposito
 	}
 
 	type QuizCreatedEvent struct {
-		InstanceID string `json:"instance_id"`
-		Title      string `json:"title"`
-		GroupID    string `json:"group_id,omitempty"`
-		CreatorID  string `json:"creator_id"`
-		Deadline   string `json:"deadline,omitempty"`
+		InstanceID   string   `json:"instance_id"`
+		Title        string   `json:"title"`
+		GroupID      string   `json:"group_id,omitempty"`
+		CreatorID    string   `json:"creator_id"`
+		Deadline     string   `json:"deadline,omitempty"`
+		Participants []string `json:"participants"`
 	}
 
 	event := QuizCreatedEvent{
-		InstanceID: instance.ID,
-		Title:      instance.Title,
-		CreatorID:  instance.CreatedBy,
+		InstanceID:   instance.ID,
+		Title:        instance.Title,
+		CreatorID:    instance.CreatedBy,
+		Participants: []string{},
 	}
 
 	if instance.GroupID.Valid {
 		event.GroupID = instance.GroupID.String
+
+		memberIDs, err := s.userClient.GetGroupMemberIDs(ctx, instance.GroupID.String)
+		if err != nil {
+			log.Printf("Failed to get members of group %s: %v", instance.GroupID.String, err)
+		}
+		for _, memberID := range memberIDs {
+			if memberID != instance.CreatedBy {
+				event.Participants = append(event.Participants, memberID)
+			}
+		}
 	}
 
 	if instance.Deadline.Valid {
@@ -515,3 +810,124 @@ func (s *QuizService) publishQuizCreated(ctx context.Context, instance *reposito
 		log.Printf("Failed to publish quiz_created event: %v", err)
 	}
 }
+
+func (s *QuizService) publishQuizStarted(ctx context.Context, instance *repository.Instance) {
+	if s.mqPublisher == nil {
+		return
+	}
+
+	type QuizStartedEvent struct {
+		InstanceID   string   `json:"instance_id"`
+		Title        string   `json:"title"`
+		GroupID      string   `json:"group_id,omitempty"`
+		CreatorID    string   `json:"creator_id"`
+		Deadline     string   `json:"deadline,omitempty"`
+		Participants []string `json:"participants"`
+	}
+
+	event := QuizStartedEvent{
+		InstanceID:   instance.ID,
+		Title:        instance.Title,
+		CreatorID:    instance.CreatedBy,
+		Participants: s.instanceParticipantIDs(ctx, instance.ID, instance.CreatedBy),
+	}
+
+	if instance.GroupID.Valid {
+		event.GroupID = instance.GroupID.String
+	}
+
+	if instance.Deadline.Valid {
+		event.Deadline = instance.Deadline.Time.Format(time.RFC3339)
+	}
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to marshal quiz_started event: %v", err)
+		return
+	}
+
+	if err := s.mqPublisher.Publish(ctx, "quiz.started", eventJSON); err != nil {
+		log.Printf("Failed to publish quiz_started event: %v", err)
+	}
+}
+
+func (s *QuizService) publishQuizFinished(ctx context.Context, instance *repository.Instance) {
+	if s.mqPublisher == nil {
+		return
+	}
+
+	type QuizFinishedEvent struct {
+		InstanceID     string   `json:"instance_id"`
+		Title          string   `json:"title"`
+		Status         string   `json:"status"`
+		CreatorID      string   `json:"creator_id"`
+		ParticipantIDs []string `json:"participant_ids"`
+	}
+
+	eventJSON, err := json.Marshal(QuizFinishedEvent{
+		InstanceID:     instance.ID,
+		Title:          instance.Title,
+		Status:         instance.Status,
+		CreatorID:      instance.CreatedBy,
+		ParticipantIDs: s.instanceParticipantIDs(ctx, instance.ID, instance.CreatedBy),
+	})
+	if err != nil {
+		log.Printf("Failed to marshal quiz_finished event: %v", err)
+		return
+	}
+
+	if err := s.mqPublisher.Publish(ctx, "quiz.finished", eventJSON); err != nil {
+		log.Printf("Failed to publish quiz_finished event: %v", err)
+	}
+}
+
+// resultsReadyEvent is the quiz.results_ready payload. game-service
+// publishes the same fields when a quiz ends without open questions.
+type resultsReadyEvent struct {
+	InstanceID     string              `json:"instance_id"`
+	Title          string              `json:"title"`
+	ParticipantIDs []string            `json:"participant_ids"`
+	Results        []participantResult `json:"results"`
+}
+
+type participantResult struct {
+	UserID string `json:"user_id"`
+	Score  int    `json:"score"`
+	Rank   int    `json:"rank"`
+}
+
+// publishResultsReady tells notification-service that the final scores and
+// ranks of the instance are available.
+func (s *QuizService) publishResultsReady(ctx context.Context, instance *repository.Instance, sessions []*repository.Session) {
+	if s.mqPublisher == nil {
+		return
+	}
+
+	results, totalTimes := participantResults(nil, sessions, instance.CreatedBy)
+	rankResults(results, totalTimes, s.instanceToProto(instance).Settings.GetLeaderboardRanking())
+
+	event := resultsReadyEvent{
+		InstanceID:     instance.ID,
+		Title:          instance.Title,
+		ParticipantIDs: []string{},
+		Results:        []participantResult{},
+	}
+	for _, result := range results {
+		event.ParticipantIDs = append(event.ParticipantIDs, result.UserId)
+		event.Results = append(event.Results, participantResult{
+			UserID: result.UserId,
+			Score:  int(result.Score),
+			Rank:   int(result.Rank),
+		})
+	}
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to marshal quiz_results_ready event: %v", err)
+		return
+	}
+
+	if err := s.mqPublisher.Publish(ctx, "quiz.results_ready", eventJSON); err != nil {
+		log.Printf("Failed to publish quiz_results_ready event: %v", err)
+	}
+}
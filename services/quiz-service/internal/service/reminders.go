@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"quiz-service/internal/repository"
+	pb "quiz-service/proto"
+)
+
+// maxReminderWindow is the earliest a reminder can go out before a deadline,
+// matching the longest deadline_reminder setting.
+const maxReminderWindow = 24 * time.Hour
+
+// reminderWindow converts a deadline_reminder setting to how long before the
+// deadline the user wants to be reminded. Zero means never.
+func reminderWindow(setting string) time.Duration {
+	switch setting {
+	case "1h":
+		return time.Hour
+	case "24h":
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// dueForReminder reports whether a user with the given setting should be
+// reminded now about a quiz closing at deadline.
+func dueForReminder(setting string, deadline, now time.Time) bool {
+	window := reminderWindow(setting)
+	return window > 0 && now.Before(deadline) && deadline.Sub(now) <= window
+}
+
+// sendDeadlineReminders reminds the group members who have not finished an
+// async quiz that its deadline is approaching.
+func (s *QuizService) sendDeadlineReminders(ctx context.Context, now time.Time) {
+	if s.mqPublisher == nil {
+		return
+	}
+
+	instances, err := s.instanceRepo.GetInstancesNearDeadline(ctx, now, maxReminderWindow)
+	if err != nil {
+		log.Printf("Failed to get instances near deadline: %v", err)
+		return
+	}
+
+	for _, instance := range instances {
+		s.remindInstanceMembers(ctx, instance, now)
+	}
+}
+
+func (s *QuizService) remindInstanceMembers(ctx context.Context, instance *repository.Instance, now time.Time) {
+	memberIDs, err := s.userClient.GetGroupMemberIDs(ctx, instance.GroupID.String)
+	if err != nil {
+		log.Printf("Failed to get members of group %s: %v", instance.GroupID.String, err)
+		return
+	}
+
+	sessions, err := s.sessionRepo.GetSessionsByInstance(ctx, instance.ID)
+	if err != nil {
+		log.Printf("Failed to get sessions of instance %s: %v", instance.ID, err)
+		return
+	}
+	done := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		if session.Status == "finished" || session.Status == "kicked" {
+			done[session.UserID] = true
+		}
+	}
+
+	reminded, err := s.instanceRepo.GetRemindedUserIDs(ctx, instance.ID)
+	if err != nil {
+		log.Printf("Failed to get reminded users of instance %s: %v", instance.ID, err)
+		return
+	}
+	for _, userID := range reminded {
+		done[userID] = true
+	}
+
+	var pending []string
+	for _, memberID := range memberIDs {
+		if memberID != instance.CreatedBy && !done[memberID] {
+			pending = append(pending, memberID)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	settings, err := s.userClient.GetNotificationSettingsByUserIDs(ctx, pending)
+	if err != nil {
+		log.Printf("Failed to get notification settings of group %s: %v", instance.GroupID.String, err)
+		return
+	}
+
+	var due []string
+	for _, memberID := range pending {
+		st, ok := settings[memberID]
+		if ok && dueForReminder(st.DeadlineReminder, instance.Deadline.Time, now) {
+			due = append(due, memberID)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	users, err := s.userClient.GetUsersByIDs(ctx, due)
+	if err != nil {
+		log.Printf("Failed to get users to remind: %v", err)
+		return
+	}
+
+	for _, userID := range due {
+		user, ok := users[userID]
+		if !ok {
+			continue
+		}
+
+		claimed, err := s.instanceRepo.ClaimDeadlineReminder(ctx, instance.ID, userID)
+		if err != nil {
+			log.Printf("Failed to record deadline reminder for user %s: %v", userID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.publishDeadlineReminder(ctx, instance, user); err != nil {
+			log.Printf("Failed to send deadline reminder to user %s: %v", userID, err)
+			if err := s.instanceRepo.ReleaseDeadlineReminder(ctx, instance.ID, userID); err != nil {
+				log.Printf("Failed to release deadline reminder for user %s: %v", userID, err)
+			}
+		}
+	}
+}
+
+func (s *QuizService) publishDeadlineReminder(ctx context.Context, instance *repository.Instance, user *pb.User) error {
+	type DeadlineReminderEvent struct {
+		InstanceID string `json:"instance_id"`
+		Title      string `json:"title"`
+		Deadline   string `json:"deadline"`
+		UserID     string `json:"user_id"`
+		Email      string `json:"email,omitempty"`
+	}
+
+	eventJSON, err := json.Marshal(DeadlineReminderEvent{
+		InstanceID: instance.ID,
+		Title:      instance.Title,
+		Deadline:   instance.Deadline.Time.Format(time.RFC3339),
+		UserID:     user.Id,
+		Email:      user.Email,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal deadline_reminder event: %w", err)
+	}
+
+	if err := s.mqPublisher.Publish(ctx, "quiz.deadline_reminder", eventJSON); err != nil {
+		return fmt.Errorf("failed to publish deadline_reminder event: %w", err)
+	}
+	return nil
+}
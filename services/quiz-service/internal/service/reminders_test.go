@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	pb "quiz-service/proto"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestDueForReminder(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		setting  string
+		deadline time.Time
+		want     bool
+	}{
+		{"24h", now.Add(23 * time.Hour), true},
+		{"24h", now.Add(25 * time.Hour), false},
+		{"1h", now.Add(23 * time.Hour), false},
+		{"1h", now.Add(30 * time.Minute), true},
+		{"1h", now.Add(-time.Minute), false},
+		{"never", now.Add(30 * time.Minute), false},
+		{"", now.Add(30 * time.Minute), false},
+	}
+
+	for _, tt := range tests {
+		if got := dueForReminder(tt.setting, tt.deadline, now); got != tt.want {
+			t.Errorf("dueForReminder(%q, now+%s) = %v, want %v", tt.setting, tt.deadline.Sub(now), got, tt.want)
+		}
+	}
+}
+
+type fakeUserClient struct {
+	UserClient
+	members       []string
+	settings      map[string]*pb.NotificationSettings
+	settingsCalls [][]string
+}
+
+func (f *fakeUserClient) GetGroupMemberIDs(ctx context.Context, groupID string) ([]string, error) {
+	return f.members, nil
+}
+
+func (f *fakeUserClient) GetNotificationSettingsByUserIDs(ctx context.Context, userIDs []string) (map[string]*pb.NotificationSettings, error) {
+	f.settingsCalls = append(f.settingsCalls, userIDs)
+	return f.settings, nil
+}
+
+func (f *fakeUserClient) GetUsersByIDs(ctx context.Context, userIDs []string) (map[string]*pb.User, error) {
+	users := make(map[string]*pb.User, len(userIDs))
+	for _, id := range userIDs {
+		users[id] = &pb.User{Id: id, Email: id + "@example.com"}
+	}
+	return users, nil
+}
+
+func TestDeadlineRemindersBatchSettingsLookup(t *testing.T) {
+	s, mock, publisher := newSchedulerTestService(t)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	deadline := now.Add(30 * time.Minute)
+
+	users := &fakeUserClient{
+		members: []string{"host", "done", "hourly", "daily", "quiet"},
+		settings: map[string]*pb.NotificationSettings{
+			"hourly": {UserId: "hourly", DeadlineReminder: "1h"},
+			"daily":  {UserId: "daily", DeadlineReminder: "24h"},
+			"quiet":  {UserId: "quiet", DeadlineReminder: "never"},
+		},
+	}
+	s.userClient = users
+
+	mock.ExpectQuery(`FROM quiz_instances\s+WHERE status IN`).
+		WillReturnRows(sqlmock.NewRows(instanceColumns).
+			AddRow("inst-1", nil, "Capitals", "ABC123", "active", "group-1", "host", now.Add(-time.Hour), nil, deadline, "async", `{}`))
+	mock.ExpectQuery(`FROM game_sessions`).
+		WithArgs("inst-1").
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow("inst-1", "done", "finished", 3, 20, "[]", now.Add(-time.Hour), now))
+	mock.ExpectQuery(`FROM deadline_reminders`).
+		WithArgs("inst-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("daily"))
+	mock.ExpectExec(`INSERT INTO deadline_reminders`).
+		WithArgs("inst-1", "hourly").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.sendDeadlineReminders(context.Background(), now)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if len(users.settingsCalls) != 1 || !slices.Equal(users.settingsCalls[0], []string{"hourly", "quiet"}) {
+		t.Fatalf("settings lookups = %v, want one for [hourly quiet]", users.settingsCalls)
+	}
+	if got := len(publisher.events["quiz.deadline_reminder"]); got != 1 {
+		t.Fatalf("published %d reminders, want 1", got)
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+
+	"quiz-service/internal/repository"
+	pb "quiz-service/proto"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GetInstanceResults returns every participant's answers for export, ranked
+// like the final leaderboard.
+func (s *QuizService) GetInstanceResults(ctx context.Context, req *pb.GetInstanceResultsRequest) (*pb.GetInstanceResultsResponse, error) {
+	instanceWithQuestions, err := s.instanceRepo.GetInstanceWithQuestions(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	instance := instanceWithQuestions.Instance
+	if instance.CreatedBy != req.UserId {
+		return &pb.GetInstanceResultsResponse{
+			HasAccess:    false,
+			ErrorMessage: "Only the quiz creator can export results",
+		}, nil
+	}
+
+	sessions, err := s.sessionRepo.GetSessionsByInstance(ctx, req.InstanceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	protoInstance := s.instanceToProto(instance)
+	results, totalTimes := participantResults(instanceWithQuestions.Questions, sessions, instance.CreatedBy)
+	rankResults(results, totalTimes, protoInstance.Settings.GetLeaderboardRanking())
+	s.addProfiles(ctx, results)
+
+	return &pb.GetInstanceResultsResponse{
+		Instance:  protoInstance,
+		Questions: s.questionsToProto(instanceWithQuestions.Questions),
+		Results:   results,
+		HasAccess: true,
+	}, nil
+}
+
+// participantResults builds a result per participant, with an entry for every
+// question, and each participant's total answer time for ranking.
+func participantResults(questions []*repository.Question, sessions []*repository.Session, createdBy string) ([]*pb.ParticipantResult, map[string]int64) {
+	var results []*pb.ParticipantResult
+	totalTimes := make(map[string]int64)
+	for _, session := range sessions {
+		if session.UserID == createdBy || session.Status == "kicked" {
+			continue
+		}
+
+		var answers []repository.SessionAnswer
+		if err := json.Unmarshal([]byte(session.Answers), &answers); err != nil {
+			log.Printf("Failed to parse answers of user %s: %v", session.UserID, err)
+		}
+		byQuestion := make(map[string]repository.SessionAnswer, len(answers))
+		for _, a := range answers {
+			byQuestion[a.QuestionID] = a
+			totalTimes[session.UserID] += a.TimeSpentMs
+		}
+
+		result := &pb.ParticipantResult{
+			UserId: session.UserID,
+			Status: session.Status,
+			Score:  int32(session.Score),
+		}
+		if session.FinishedAt.Valid {
+			result.FinishedAt = timestamppb.New(session.FinishedAt.Time)
+		}
+		for _, q := range questions {
+			answer := &pb.QuestionResult{QuestionId: q.ID}
+			if a, ok := byQuestion[q.ID]; ok {
+				answer.Answered = true
+				answer.Answer = a.Answer
+				answer.IsCorrect = a.IsCorrect
+				answer.Score = int32(a.Score)
+				answer.TimeSpentMs = a.TimeSpentMs
+			}
+			result.Answers = append(result.Answers, answer)
+		}
+		results = append(results, result)
+	}
+	return results, totalTimes
+}
+
+// rankResults orders results as game-service ranks its leaderboard: by score,
+// then by less total answer time. Equal results share a rank; dense ranking
+// does not skip the ranks they took.
+func rankResults(results []*pb.ParticipantResult, totalTimes map[string]int64, ranking string) {
+	slices.SortFunc(results, func(a, b *pb.ParticipantResult) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(totalTimes[a.UserId], totalTimes[b.UserId]),
+			cmp.Compare(a.UserId, b.UserId),
+		)
+	})
+
+	for i, result := range results {
+		if i == 0 {
+			result.Rank = 1
+			continue
+		}
+		prev := results[i-1]
+		switch {
+		case result.Score == prev.Score && totalTimes[result.UserId] == totalTimes[prev.UserId]:
+			result.Rank = prev.Rank
+		case ranking == "dense":
+			result.Rank = prev.Rank + 1
+		default:
+			result.Rank = int32(i + 1)
+		}
+	}
+}
+
+// addProfiles fills in names and emails. Results are still returned without
+// them if user-service is unavailable.
+func (s *QuizService) addProfiles(ctx context.Context, results []*pb.ParticipantResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	userIDs := make([]string, len(results))
+	for i, result := range results {
+		userIDs[i] = result.UserId
+	}
+
+	users, err := s.userClient.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		log.Printf("Failed to get participant profiles: %v", err)
+		return
+	}
+
+	for _, result := range results {
+		if user, ok := users[result.UserId]; ok {
+			result.FirstName = user.FirstName
+			result.LastName = user.LastName
+			result.Email = user.Email
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"quiz-service/internal/repository"
+)
+
+func TestParticipantResultsRanking(t *testing.T) {
+	questions := []*repository.Question{{ID: "q1"}, {ID: "q2"}}
+	sessions := []*repository.Session{
+		analyticsSession("host", 0),
+		analyticsSession("slow", 10, repository.SessionAnswer{QuestionID: "q1", Answer: "0", Score: 10, TimeSpentMs: 5000}),
+		analyticsSession("fast", 10, repository.SessionAnswer{QuestionID: "q2", Answer: "1", Score: 10, TimeSpentMs: 1000}),
+		analyticsSession("tied", 10, repository.SessionAnswer{QuestionID: "q1", Answer: "0", Score: 10, TimeSpentMs: 5000}),
+		analyticsSession("last", 0),
+	}
+
+	results, totalTimes := participantResults(questions, sessions, "host")
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+
+	rankResults(results, totalTimes, "competition")
+	var order []string
+	var ranks []int32
+	for _, r := range results {
+		order = append(order, r.UserId)
+		ranks = append(ranks, r.Rank)
+	}
+	if want := []string{"fast", "slow", "tied", "last"}; !slices.Equal(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	if want := []int32{1, 2, 2, 4}; !slices.Equal(ranks, want) {
+		t.Fatalf("competition ranks = %v, want %v", ranks, want)
+	}
+
+	rankResults(results, totalTimes, "dense")
+	if results[3].Rank != 3 {
+		t.Fatalf("dense rank of last = %d, want 3", results[3].Rank)
+	}
+
+	fast := results[0]
+	if len(fast.Answers) != 2 || fast.Answers[0].Answered || !fast.Answers[1].Answered || fast.Answers[1].TimeSpentMs != 1000 {
+		t.Fatalf("answers = %v", fast.Answers)
+	}
+}
+
+type recordingPublisher struct {
+	events map[string][][]byte
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, queueName string, body []byte) error {
+	if p.events == nil {
+		p.events = make(map[string][][]byte)
+	}
+	p.events[queueName] = append(p.events[queueName], body)
+	return nil
+}
+
+func TestPublishResultsReadyRanksParticipants(t *testing.T) {
+	publisher := &recordingPublisher{}
+	s := &QuizService{mqPublisher: publisher}
+
+	instance := &repository.Instance{ID: "inst-1", Title: "Capitals", CreatedBy: "host", Settings: `{}`}
+	kicked := analyticsSession("kicked", 30)
+	kicked.Status = "kicked"
+	s.publishResultsReady(context.Background(), instance, []*repository.Session{
+		analyticsSession("host", 0),
+		analyticsSession("second", 5, repository.SessionAnswer{QuestionID: "q1", TimeSpentMs: 1000}),
+		analyticsSession("first", 10, repository.SessionAnswer{QuestionID: "q1", TimeSpentMs: 4000}),
+		kicked,
+	})
+
+	events := publisher.events["quiz.results_ready"]
+	if len(events) != 1 {
+		t.Fatalf("published %d results_ready events, want 1", len(events))
+	}
+	var event resultsReadyEvent
+	if err := json.Unmarshal(events[0], &event); err != nil {
+		t.Fatalf("invalid event: %v", err)
+	}
+	if event.InstanceID != "inst-1" || event.Title != "Capitals" || !slices.Equal(event.ParticipantIDs, []string{"first", "second"}) {
+		t.Fatalf("event = %+v", event)
+	}
+	want := []participantResult{{UserID: "first", Score: 10, Rank: 1}, {UserID: "second", Score: 5, Rank: 2}}
+	if !slices.Equal(event.Results, want) {
+		t.Fatalf("results = %+v, want %+v", event.Results, want)
+	}
+}
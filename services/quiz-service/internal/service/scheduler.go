@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"quiz-service/internal/repository"
+)
+
+// RunScheduler opens async instances at their start time, reminds members of
+// approaching deadlines and closes instances at their deadline, checking every
+// interval until ctx is cancelled.
+func (s *QuizService) RunScheduler(ctx context.Context, interval time.Duration) {
+	log.Printf("Instance scheduler started: interval=%s", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.runScheduledTransitions(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *QuizService) runScheduledTransitions(ctx context.Context, now time.Time) {
+	started, err := s.instanceRepo.StartScheduledInstances(ctx, now)
+	if err != nil {
+		log.Printf("Failed to start scheduled instances: %v", err)
+	}
+	for _, instance := range started {
+		log.Printf("Scheduled instance %s started", instance.ID)
+		s.publishQuizStarted(ctx, instance)
+		s.publishInstanceStarted(ctx, instance.ID)
+	}
+
+	s.sendDeadlineReminders(ctx, now)
+
+	closed, finished, err := s.instanceRepo.CloseExpiredInstances(ctx, now)
+	if err != nil {
+		log.Printf("Failed to close expired instances: %v", err)
+	}
+	for _, instance := range closed {
+		log.Printf("Instance %s closed at deadline with status %s", instance.ID, instance.Status)
+		s.finishClosedInstance(ctx, instance, finished)
+	}
+}
+
+// publishInstanceStarted tells game-service to start the quiz for the
+// participants already waiting in the instance.
+func (s *QuizService) publishInstanceStarted(ctx context.Context, instanceID string) {
+	if s.mqPublisher == nil {
+		return
+	}
+
+	type InstanceStartedEvent struct {
+		InstanceID string `json:"instance_id"`
+	}
+
+	eventJSON, err := json.Marshal(InstanceStartedEvent{InstanceID: instanceID})
+	if err != nil {
+		log.Printf("Failed to marshal instance_started event: %v", err)
+		return
+	}
+
+	if err := s.mqPublisher.Publish(ctx, "game.instance_started", eventJSON); err != nil {
+		log.Printf("Failed to publish instance_started event: %v", err)
+	}
+}
+
+// finishClosedInstance requests grading for the sessions the close finished
+// and announces the end. quiz-service owns the deadline of async instances:
+// game-service only ends the quiz for participants still connected, and
+// grades the sessions it finishes itself.
+func (s *QuizService) finishClosedInstance(ctx context.Context, instance *repository.Instance, finished []*repository.Session) {
+	if instance.Status == "pending_review" {
+		var sessions []*repository.Session
+		for _, session := range finished {
+			if session.InstanceID == instance.ID {
+				sessions = append(sessions, session)
+			}
+		}
+		s.requestGrading(ctx, instance.ID, sessions)
+	}
+
+	s.publishQuizFinished(ctx, instance)
+
+	if instance.Status == "finished" {
+		sessions, err := s.sessionRepo.GetSessionsByInstance(ctx, instance.ID)
+		if err != nil {
+			log.Printf("Failed to get sessions of instance %s: %v", instance.ID, err)
+			return
+		}
+		s.publishResultsReady(ctx, instance, sessions)
+	}
+}
+
+// requestGrading asks ml-service for grading suggestions on the sessions'
+// open answers that wait for the creator's review.
+func (s *QuizService) requestGrading(ctx context.Context, instanceID string, sessions []*repository.Session) {
+	if s.mqPublisher == nil || len(sessions) == 0 {
+		return
+	}
+
+	instanceWithQuestions, err := s.instanceRepo.GetInstanceWithQuestions(ctx, instanceID)
+	if err != nil {
+		log.Printf("Failed to get questions of instance %s: %v", instanceID, err)
+		return
+	}
+	questions := make(map[string]*repository.Question, len(instanceWithQuestions.Questions))
+	for _, q := range instanceWithQuestions.Questions {
+		questions[q.ID] = q
+	}
+
+	type GradingAnswerData struct {
+		QuestionID    string `json:"question_id"`
+		QuestionText  string `json:"question_text"`
+		Answer        string `json:"answer"`
+		CorrectAnswer string `json:"correct_answer"`
+		MaxScore      int    `json:"max_score"`
+	}
+
+	type GradingRequestEvent struct {
+		InstanceID string              `json:"instance_id"`
+		UserID     string              `json:"user_id"`
+		Answers    []GradingAnswerData `json:"answers"`
+	}
+
+	for _, session := range sessions {
+		var answers []repository.SessionAnswer
+		if err := json.Unmarshal([]byte(session.Answers), &answers); err != nil {
+			log.Printf("Failed to parse answers of user %s: %v", session.UserID, err)
+			continue
+		}
+
+		event := GradingRequestEvent{
+			InstanceID: instanceID,
+			UserID:     session.UserID,
+		}
+		for _, a := range answers {
+			q, ok := questions[a.QuestionID]
+			if !ok || q.Type != "open" || !a.NeedsReview {
+				continue
+			}
+
+			var correct string
+			if err := json.Unmarshal([]byte(q.CorrectAnswer), &correct); err != nil {
+				correct = q.CorrectAnswer
+			}
+			event.Answers = append(event.Answers, GradingAnswerData{
+				QuestionID:    q.ID,
+				QuestionText:  q.Text,
+				Answer:        a.Answer,
+				CorrectAnswer: correct,
+				MaxScore:      q.MaxScore,
+			})
+		}
+		if len(event.Answers) == 0 {
+			continue
+		}
+
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("Failed to marshal grading_request event: %v", err)
+			continue
+		}
+
+		if err := s.mqPublisher.Publish(ctx, "ml.grading_requests", eventJSON); err != nil {
+			log.Printf("Failed to publish grading_request event: %v", err)
+		}
+	}
+}
+
+// instanceParticipantIDs returns the users who joined the instance and were
+// not kicked, leaving out the creator.
+func (s *QuizService) instanceParticipantIDs(ctx context.Context, instanceID, createdBy string) []string {
+	sessions, err := s.sessionRepo.GetSessionsByInstance(ctx, instanceID)
+	if err != nil {
+		log.Printf("Failed to get sessions of instance %s: %v", instanceID, err)
+		return nil
+	}
+
+	var participantIDs []string
+	for _, session := range sessions {
+		if session.UserID == createdBy || session.Status == "kicked" {
+			continue
+		}
+		participantIDs = append(participantIDs, session.UserID)
+	}
+	return participantIDs
+}
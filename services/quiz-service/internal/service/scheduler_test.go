@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"quiz-service/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var (
+	instanceColumns = []string{"id", "template_id", "title", "access_code", "status", "group_id", "created_by", "created_at", "start_time", "deadline", "quiz_type", "settings"}
+	sessionColumns  = []string{"instance_id", "user_id", "status", "current_question_index", "score", "answers", "started_at", "finished_at"}
+)
+
+func newSchedulerTestService(t *testing.T) (*QuizService, sqlmock.Sqlmock, *recordingPublisher) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	publisher := &recordingPublisher{}
+	return NewQuizService(db, publisher, nil), mock, publisher
+}
+
+func instanceRow(rows *sqlmock.Rows, id, status string, now time.Time) *sqlmock.Rows {
+	return rows.AddRow(id, nil, "Capitals", "ABC123", status, nil, "host", now.Add(-time.Hour), now.Add(-time.Minute), now, "async", `{}`)
+}
+
+func TestScheduledTransitionsStartAndCloseInstances(t *testing.T) {
+	s, mock, publisher := newSchedulerTestService(t)
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`UPDATE quiz_instances\s+SET status = 'active'`).
+		WithArgs(now).
+		WillReturnRows(instanceRow(sqlmock.NewRows(instanceColumns), "inst-start", "active", now))
+	mock.ExpectQuery(`FROM game_sessions`).
+		WithArgs("inst-start").
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow("inst-start", "waiting-user", "joined", 0, 0, "[]", now.Add(-time.Hour), nil))
+
+	mock.ExpectQuery(`FROM quiz_instances\s+WHERE status IN`).
+		WillReturnRows(sqlmock.NewRows(instanceColumns))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE quiz_instances i`).
+		WithArgs(now).
+		WillReturnRows(instanceRow(sqlmock.NewRows(instanceColumns), "inst-close", "finished", now))
+	mock.ExpectQuery(`UPDATE game_sessions`).
+		WithArgs(sqlmock.AnyArg(), now).
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow("inst-close", "late", "finished", 1, 0, "[]", now.Add(-time.Hour), now))
+	mock.ExpectCommit()
+
+	closedSessions := func() *sqlmock.Rows {
+		return sqlmock.NewRows(sessionColumns).
+			AddRow("inst-close", "done", "finished", 3, 20, "[]", now.Add(-time.Hour), now.Add(-time.Minute)).
+			AddRow("inst-close", "late", "finished", 1, 0, "[]", now.Add(-time.Hour), now)
+	}
+	mock.ExpectQuery(`FROM game_sessions`).WithArgs("inst-close").WillReturnRows(closedSessions())
+	mock.ExpectQuery(`FROM game_sessions`).WithArgs("inst-close").WillReturnRows(closedSessions())
+
+	s.runScheduledTransitions(ctx, now)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(publisher.events["quiz.started"]); got != 1 {
+		t.Fatalf("published %d quiz.started events, want 1", got)
+	}
+	started := publisher.events["game.instance_started"]
+	if len(started) != 1 {
+		t.Fatalf("published %d game.instance_started events, want 1", len(started))
+	}
+	var startEvent struct {
+		InstanceID string `json:"instance_id"`
+	}
+	if err := json.Unmarshal(started[0], &startEvent); err != nil {
+		t.Fatalf("failed to decode instance_started event: %v", err)
+	}
+	if startEvent.InstanceID != "inst-start" {
+		t.Fatalf("instance_started for %q, want inst-start", startEvent.InstanceID)
+	}
+
+	if got := len(publisher.events["quiz.finished"]); got != 1 {
+		t.Fatalf("published %d quiz.finished events, want 1", got)
+	}
+	results := publisher.events["quiz.results_ready"]
+	if len(results) != 1 {
+		t.Fatalf("published %d results_ready events, want 1", len(results))
+	}
+	var resultsEvent resultsReadyEvent
+	if err := json.Unmarshal(results[0], &resultsEvent); err != nil {
+		t.Fatalf("failed to decode results_ready event: %v", err)
+	}
+	if len(resultsEvent.Results) != 2 || resultsEvent.Results[0].UserID != "done" || resultsEvent.Results[0].Rank != 1 {
+		t.Fatalf("results = %+v, want done ranked first of 2", resultsEvent.Results)
+	}
+	if got := len(publisher.events["ml.grading_requests"]); got != 0 {
+		t.Fatalf("published %d grading requests for an instance without open questions", got)
+	}
+}
+
+func TestScheduledCloseRequestsGradingForFinishedSessions(t *testing.T) {
+	s, mock, publisher := newSchedulerTestService(t)
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	answers, _ := json.Marshal([]repository.SessionAnswer{
+		{QuestionID: "q-open", Answer: "Paris", NeedsReview: true},
+		{QuestionID: "q-choice", Answer: "1", IsCorrect: true, Score: 10},
+	})
+
+	mock.ExpectQuery(`UPDATE quiz_instances\s+SET status = 'active'`).
+		WillReturnRows(sqlmock.NewRows(instanceColumns))
+	mock.ExpectQuery(`FROM quiz_instances\s+WHERE status IN`).
+		WillReturnRows(sqlmock.NewRows(instanceColumns))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE quiz_instances i`).
+		WithArgs(now).
+		WillReturnRows(instanceRow(sqlmock.NewRows(instanceColumns), "inst-review", "pending_review", now))
+	mock.ExpectQuery(`UPDATE game_sessions`).
+		WithArgs(sqlmock.AnyArg(), now).
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow("inst-review", "late", "finished", 2, 10, string(answers), now.Add(-time.Hour), now))
+	mock.ExpectCommit()
+
+	questionColumns := append(append([]string{}, instanceColumns...),
+		"q_id", "text", "type", "options", "correct_answer", "order_index", "max_score", "time_limit_sec", "ai_answer")
+	instanceValues := []driver.Value{"inst-review", nil, "Capitals", "ABC123", "pending_review", nil, "host", now.Add(-time.Hour), now.Add(-time.Minute), now, "async", `{}`}
+	mock.ExpectQuery(`FROM quiz_instances i\s+LEFT JOIN instance_questions`).
+		WithArgs("inst-review").
+		WillReturnRows(sqlmock.NewRows(questionColumns).
+			AddRow(append(instanceValues, "q-open", "Capital of France?", "open", nil, `"Paris"`, 0, 10, 0, nil)...).
+			AddRow(append(instanceValues, "q-choice", "Capital of Spain?", "multiple_choice", `["Rome","Madrid"]`, `"1"`, 1, 10, 0, nil)...))
+	mock.ExpectQuery(`FROM game_sessions`).
+		WithArgs("inst-review").
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow("inst-review", "late", "finished", 2, 10, string(answers), now.Add(-time.Hour), now))
+
+	s.runScheduledTransitions(ctx, now)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := publisher.events["ml.grading_requests"]
+	if len(requests) != 1 {
+		t.Fatalf("published %d grading requests, want 1", len(requests))
+	}
+	var request struct {
+		InstanceID string `json:"instance_id"`
+		UserID     string `json:"user_id"`
+		Answers    []struct {
+			QuestionID    string `json:"question_id"`
+			CorrectAnswer string `json:"correct_answer"`
+		} `json:"answers"`
+	}
+	if err := json.Unmarshal(requests[0], &request); err != nil {
+		t.Fatalf("failed to decode grading request: %v", err)
+	}
+	if request.UserID != "late" || len(request.Answers) != 1 || request.Answers[0].QuestionID != "q-open" || request.Answers[0].CorrectAnswer != "Paris" {
+		t.Fatalf("grading request = %+v, want the open answer of late", request)
+	}
+
+	if got := len(publisher.events["quiz.finished"]); got != 1 {
+		t.Fatalf("published %d quiz.finished events, want 1", got)
+	}
+	if got := len(publisher.events["quiz.results_ready"]); got != 0 {
+		t.Fatalf("published %d results_ready events before review, want 0", got)
+	}
+}
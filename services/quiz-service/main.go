@@ -103,6 +103,10 @@ func main() {
 
 	quizService := service.NewQuizService(pgClient.GetDB(), rabbitClient, userClient)
 
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go quizService.RunScheduler(schedulerCtx, time.Duration(cfg.Scheduler.IntervalSec)*time.Second)
+
 	grpcServer := grpc.NewServer()
 	pb.RegisterQuizServiceServer(grpcServer, quizService)
 	reflection.Register(grpcServer)
@@ -123,6 +127,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	stopScheduler()
 	grpcServer.GracefulStop()
 
 	log.Println("Quiz service stopped")
@@ -120,6 +120,16 @@ func (c *PostgresClient) InitSchema(ctx context.Context) error {
 		CREATE INDEX IF NOT EXISTS idx_instance_questions_instance_id ON instance_questions(instance_id);
 	`
 
+	createDeadlineRemindersTable := `
+		CREATE TABLE IF NOT EXISTS deadline_reminders (
+			instance_id VARCHAR(255) NOT NULL,
+			user_id VARCHAR(255) NOT NULL,
+			sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (instance_id, user_id),
+			FOREIGN KEY (instance_id) REFERENCES quiz_instances(id) ON DELETE CASCADE
+		);
+	`
+
 	if _, err := c.db.ExecContext(ctx, createQuizTemplatesTable); err != nil {
 		return fmt.Errorf("failed to create quiz_templates table: %w", err)
 	}
@@ -140,5 +150,9 @@ func (c *PostgresClient) InitSchema(ctx context.Context) error {
 		return fmt.Errorf("failed to create instance_questions table: %w", err)
 	}
 
+	if _, err := c.db.ExecContext(ctx, createDeadlineRemindersTable); err != nil {
+		return fmt.Errorf("failed to create deadline_reminders table: %w", err)
+	}
+
 	return nil
 }
\ No newline at end of file
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type NotificationSettings struct {
@@ -14,6 +16,8 @@ type NotificationSettings struct {
 	QuizResults      bool
 	GroupInvites     bool
 	DeadlineReminder string // "1h", "24h", "never"
+	EmailEnabled     bool
+	InAppEnabled     bool
 	UpdatedAt        time.Time
 }
 
@@ -27,7 +31,7 @@ func NewNotificationSettingsRepository(db *sql.DB) *NotificationSettingsReposito
 
 func (r *NotificationSettingsRepository) GetSettings(ctx context.Context, userID string) (*NotificationSettings, error) {
 	query := `
-		SELECT user_id, new_quizzes, quiz_results, group_invites, deadline_reminder, updated_at
+		SELECT user_id, new_quizzes, quiz_results, group_invites, deadline_reminder, email_enabled, in_app_enabled, updated_at
 		FROM user_notification_settings
 		WHERE user_id = $1
 	`
@@ -39,6 +43,8 @@ func (r *NotificationSettingsRepository) GetSettings(ctx context.Context, userID
 		&settings.QuizResults,
 		&settings.GroupInvites,
 		&settings.DeadlineReminder,
+		&settings.EmailEnabled,
+		&settings.InAppEnabled,
 		&settings.UpdatedAt,
 	)
 
@@ -52,26 +58,86 @@ func (r *NotificationSettingsRepository) GetSettings(ctx context.Context, userID
 	return settings, nil
 }
 
-func (r *NotificationSettingsRepository) CreateDefaultSettings(ctx context.Context, userID string) (*NotificationSettings, error) {
-	settings := &NotificationSettings{
+// GetSettingsByUserIDs returns the settings of the given users, with the
+// defaults for users who never saved any. Defaults are not stored.
+func (r *NotificationSettingsRepository) GetSettingsByUserIDs(ctx context.Context, userIDs []string) ([]*NotificationSettings, error) {
+	if len(userIDs) == 0 {
+		return []*NotificationSettings{}, nil
+	}
+
+	query := `
+		SELECT user_id, new_quizzes, quiz_results, group_invites, deadline_reminder, email_enabled, in_app_enabled, updated_at
+		FROM user_notification_settings
+		WHERE user_id = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query settings: %w", err)
+	}
+	defer rows.Close()
+
+	stored := make(map[string]*NotificationSettings, len(userIDs))
+	for rows.Next() {
+		settings := &NotificationSettings{}
+		if err := rows.Scan(
+			&settings.UserID,
+			&settings.NewQuizzes,
+			&settings.QuizResults,
+			&settings.GroupInvites,
+			&settings.DeadlineReminder,
+			&settings.EmailEnabled,
+			&settings.InAppEnabled,
+			&settings.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan settings: %w", err)
+		}
+		stored[settings.UserID] = settings
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	result := make([]*NotificationSettings, 0, len(userIDs))
+	for _, userID := range userIDs {
+		settings, ok := stored[userID]
+		if !ok {
+			settings = defaultSettings(userID)
+		}
+		result = append(result, settings)
+	}
+	return result, nil
+}
+
+func defaultSettings(userID string) *NotificationSettings {
+	return &NotificationSettings{
 		UserID:           userID,
 		NewQuizzes:       true,
 		QuizResults:      true,
 		GroupInvites:     true,
 		DeadlineReminder: "24h",
+		EmailEnabled:     true,
+		InAppEnabled:     true,
 		UpdatedAt:        time.Now(),
 	}
+}
+
+func (r *NotificationSettingsRepository) CreateDefaultSettings(ctx context.Context, userID string) (*NotificationSettings, error) {
+	settings := defaultSettings(userID)
 
 	query := `
-		INSERT INTO user_notification_settings (user_id, new_quizzes, quiz_results, group_invites, deadline_reminder, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO user_notification_settings (user_id, new_quizzes, quiz_results, group_invites, deadline_reminder, email_enabled, in_app_enabled, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (user_id) DO UPDATE SET
 			new_quizzes = EXCLUDED.new_quizzes,
 			quiz_results = EXCLUDED.quiz_results,
 			group_invites = EXCLUDED.group_invites,
 			deadline_reminder = EXCLUDED.deadline_reminder,
+			email_enabled = EXCLUDED.email_enabled,
+			in_app_enabled = EXCLUDED.in_app_enabled,
 			updated_at = EXCLUDED.updated_at
-		RETURNING user_id, new_quizzes, quiz_results, group_invites, deadline_reminder, updated_at
+		RETURNING user_id, new_quizzes, quiz_results, group_invites, deadline_reminder, email_enabled, in_app_enabled, updated_at
 	`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -80,6 +146,8 @@ func (r *NotificationSettingsRepository) CreateDefaultSettings(ctx context.Conte
 		settings.QuizResults,
 		settings.GroupInvites,
 		settings.DeadlineReminder,
+		settings.EmailEnabled,
+		settings.InAppEnabled,
 		settings.UpdatedAt,
 	).Scan(
 		&settings.UserID,
@@ -87,6 +155,8 @@ func (r *NotificationSettingsRepository) CreateDefaultSettings(ctx context.Conte
 		&settings.QuizResults,
 		&settings.GroupInvites,
 		&settings.DeadlineReminder,
+		&settings.EmailEnabled,
+		&settings.InAppEnabled,
 		&settings.UpdatedAt,
 	)
 
@@ -101,13 +171,15 @@ func (r *NotificationSettingsRepository) UpdateSettings(ctx context.Context, set
 	settings.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO user_notification_settings (user_id, new_quizzes, quiz_results, group_invites, deadline_reminder, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO user_notification_settings (user_id, new_quizzes, quiz_results, group_invites, deadline_reminder, email_enabled, in_app_enabled, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (user_id) DO UPDATE SET
 			new_quizzes = EXCLUDED.new_quizzes,
 			quiz_results = EXCLUDED.quiz_results,
 			group_invites = EXCLUDED.group_invites,
 			deadline_reminder = EXCLUDED.deadline_reminder,
+			email_enabled = EXCLUDED.email_enabled,
+			in_app_enabled = EXCLUDED.in_app_enabled,
 			updated_at = EXCLUDED.updated_at
 	`
 
@@ -117,6 +189,8 @@ func (r *NotificationSettingsRepository) UpdateSettings(ctx context.Context, set
 		settings.QuizResults,
 		settings.GroupInvites,
 		settings.DeadlineReminder,
+		settings.EmailEnabled,
+		settings.InAppEnabled,
 		settings.UpdatedAt,
 	)
 
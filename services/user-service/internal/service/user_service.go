@@ -121,6 +121,28 @@ func (s *UserService) GetProfileByEmail(ctx context.Context, req *pb.GetProfileB
 	}, nil
 }
 
+func (s *UserService) GetUsersByIDs(ctx context.Context, req *pb.GetUsersByIDsRequest) (*pb.GetUsersByIDsResponse, error) {
+	users, err := s.userRepo.GetUsersByIDs(ctx, req.UserIds)
+	if err != nil {
+		log.Printf("Failed to get users by ids: %v", err)
+		return &pb.GetUsersByIDsResponse{
+			Success: false,
+			Message: "Failed to get users",
+		}, nil
+	}
+
+	pbUsers := make([]*pb.User, len(users))
+	for i, user := range users {
+		pbUsers[i] = s.userToProto(user)
+	}
+
+	return &pb.GetUsersByIDsResponse{
+		Success: true,
+		Users:   pbUsers,
+		Message: "Users retrieved successfully",
+	}, nil
+}
+
 func (s *UserService) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest) (*pb.UpdateProfileResponse, error) {
 	user, err := s.userRepo.GetUserByID(ctx, req.UserId)
 	if err != nil {
@@ -181,6 +203,28 @@ func (s *UserService) GetNotificationSettings(ctx context.Context, req *pb.GetNo
 	}, nil
 }
 
+func (s *UserService) GetNotificationSettingsByUserIDs(ctx context.Context, req *pb.GetNotificationSettingsByUserIDsRequest) (*pb.GetNotificationSettingsByUserIDsResponse, error) {
+	settings, err := s.settingsRepo.GetSettingsByUserIDs(ctx, req.UserIds)
+	if err != nil {
+		log.Printf("Failed to get notification settings by user ids: %v", err)
+		return &pb.GetNotificationSettingsByUserIDsResponse{
+			Success: false,
+			Message: "Failed to retrieve settings",
+		}, nil
+	}
+
+	pbSettings := make([]*pb.NotificationSettings, len(settings))
+	for i, st := range settings {
+		pbSettings[i] = s.settingsToProto(st)
+	}
+
+	return &pb.GetNotificationSettingsByUserIDsResponse{
+		Success:  true,
+		Settings: pbSettings,
+		Message:  "Settings retrieved successfully",
+	}, nil
+}
+
 func (s *UserService) UpdateNotificationSettings(ctx context.Context, req *pb.UpdateNotificationSettingsRequest) (*pb.UpdateNotificationSettingsResponse, error) {
 	log.Printf("UpdateNotificationSettings for user %s: NewQuizzes=%v, QuizResults=%v, GroupInvites=%v, DeadlineReminder=%v",
 		req.UserId, req.NewQuizzes, req.QuizResults, req.GroupInvites, req.DeadlineReminder)
@@ -213,6 +257,14 @@ func (s *UserService) UpdateNotificationSettings(ctx context.Context, req *pb.Up
 		log.Printf("Updating DeadlineReminder from %s to %s", settings.DeadlineReminder, *req.DeadlineReminder)
 		settings.DeadlineReminder = *req.DeadlineReminder
 	}
+	if req.EmailEnabled != nil {
+		log.Printf("Updating EmailEnabled from %v to %v", settings.EmailEnabled, *req.EmailEnabled)
+		settings.EmailEnabled = *req.EmailEnabled
+	}
+	if req.InAppEnabled != nil {
+		log.Printf("Updating InAppEnabled from %v to %v", settings.InAppEnabled, *req.InAppEnabled)
+		settings.InAppEnabled = *req.InAppEnabled
+	}
 
 	log.Printf("Updated settings before save: NewQuizzes=%v, QuizResults=%v, GroupInvites=%v, DeadlineReminder=%s",
 		settings.NewQuizzes, settings.QuizResults, settings.GroupInvites, settings.DeadlineReminder)
@@ -504,6 +556,26 @@ func (s *UserService) CheckGroupMembership(ctx context.Context, req *pb.CheckGro
 	}, nil
 }
 
+// GetGroupMemberIDs lists the IDs of a group's members for other services,
+// e.g. to address notifications. It is internal only and does no access
+// check, so api-gateway must never expose it.
+func (s *UserService) GetGroupMemberIDs(ctx context.Context, req *pb.GetGroupMemberIDsRequest) (*pb.GetGroupMemberIDsResponse, error) {
+	memberIDs, err := s.groupRepo.GetMemberIDs(ctx, req.GroupId)
+	if err != nil {
+		log.Printf("Failed to get group member ids: %v", err)
+		return &pb.GetGroupMemberIDsResponse{
+			Success: false,
+			Message: "Failed to retrieve members",
+		}, nil
+	}
+
+	return &pb.GetGroupMemberIDsResponse{
+		Success:   true,
+		MemberIds: memberIDs,
+		Message:   "Members retrieved successfully",
+	}, nil
+}
+
 func (s *UserService) userToProto(user *repository.User) *pb.User {
 	return &pb.User{
 		Id:           user.ID,
@@ -523,6 +595,8 @@ func (s *UserService) settingsToProto(settings *repository.NotificationSettings)
 		QuizResults:      settings.QuizResults,
 		GroupInvites:     settings.GroupInvites,
 		DeadlineReminder: settings.DeadlineReminder,
+		EmailEnabled:     settings.EmailEnabled,
+		InAppEnabled:     settings.InAppEnabled,
 		UpdatedAt:        settings.UpdatedAt.Unix(),
 	}
 }